@@ -0,0 +1,368 @@
+package gons3
+
+import (
+	"io"
+)
+
+// Client provides handles to GNS3 resources that carry their ids and the
+// GNS3Client, built on top of the functional API. Handles cache the last
+// known model of their resource, which is updated by every call that returns
+// it and by Refresh. Handles are not safe for concurrent use.
+type Client struct {
+	g GNS3Client
+}
+
+// NewClient creates a Client that sends requests with g.
+func NewClient(g GNS3Client) *Client {
+	return &Client{g: g}
+}
+
+// GNS3Client returns the underlying GNS3Client.
+func (c *Client) GNS3Client() GNS3Client {
+	return c.g
+}
+
+// Project returns a handle to the project with the specified id without
+// sending a request. Call Refresh to load its model.
+func (c *Client) Project(projectID string) *ProjectHandle {
+	return &ProjectHandle{g: c.g, model: Project{ProjectID: projectID}}
+}
+
+// Projects gets handles to all the GNS3 projects.
+func (c *Client) Projects() ([]*ProjectHandle, error) {
+	projects, err := GetProjects(c.g)
+	if err != nil {
+		return nil, err
+	}
+	handles := make([]*ProjectHandle, len(projects))
+	for i, p := range projects {
+		handles[i] = &ProjectHandle{g: c.g, model: p}
+	}
+	return handles, nil
+}
+
+// CreateProject creates a GNS3 project and returns its handle.
+func (c *Client) CreateProject(p ProjectCreator) (*ProjectHandle, error) {
+	proj, err := CreateProject(c.g, p)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectHandle{g: c.g, model: proj}, nil
+}
+
+// ProjectHandle is a handle to a GNS3 project.
+type ProjectHandle struct {
+	g     GNS3Client
+	model Project
+}
+
+// ID returns the project id.
+func (p *ProjectHandle) ID() string {
+	return p.model.ProjectID
+}
+
+// Model returns the last known model of the project.
+func (p *ProjectHandle) Model() Project {
+	return p.model
+}
+
+// Refresh gets the current model of the project.
+func (p *ProjectHandle) Refresh() error {
+	return p.set(GetProject(p.g, p.ID()))
+}
+
+// Update updates the project.
+func (p *ProjectHandle) Update(u ProjectUpdater) error {
+	return p.set(UpdateProject(p.g, p.ID(), u))
+}
+
+// Open opens the project.
+func (p *ProjectHandle) Open() error {
+	return p.set(OpenProject(p.g, p.ID()))
+}
+
+// Close closes the project.
+func (p *ProjectHandle) Close() error {
+	return p.set(CloseProject(p.g, p.ID()))
+}
+
+// Delete deletes the project.
+func (p *ProjectHandle) Delete() error {
+	return DeleteProject(p.g, p.ID())
+}
+
+// ReadFile reads a file of the project.
+func (p *ProjectHandle) ReadFile(filepath string) ([]byte, error) {
+	return ReadProjectFile(p.g, p.ID(), filepath)
+}
+
+// WriteFile writes a file of the project.
+func (p *ProjectHandle) WriteFile(filepath string, data []byte) error {
+	return WriteProjectFile(p.g, p.ID(), filepath, data)
+}
+
+// ReadFileTo streams a file of the project to w.
+func (p *ProjectHandle) ReadFileTo(filepath string, w io.Writer, progress ProgressFunc) error {
+	return ReadProjectFileTo(p.g, p.ID(), filepath, w, progress)
+}
+
+// WriteFileFrom streams r to a file of the project.
+func (p *ProjectHandle) WriteFileFrom(filepath string, r io.Reader, progress ProgressFunc) error {
+	return WriteProjectFileFrom(p.g, p.ID(), filepath, r, progress)
+}
+
+// Nodes returns a handle to the nodes of the project.
+func (p *ProjectHandle) Nodes() *NodesHandle {
+	return &NodesHandle{g: p.g, projectID: p.ID()}
+}
+
+// Node returns a handle to the node with the specified id without sending a
+// request. Call Refresh to load its model.
+func (p *ProjectHandle) Node(nodeID string) *NodeHandle {
+	return &NodeHandle{g: p.g, model: Node{ProjectID: p.ID(), NodeID: nodeID}}
+}
+
+// Links returns a handle to the links of the project.
+func (p *ProjectHandle) Links() *LinksHandle {
+	return &LinksHandle{g: p.g, projectID: p.ID()}
+}
+
+// Link returns a handle to the link with the specified id without sending a
+// request. Call Refresh to load its model.
+func (p *ProjectHandle) Link(linkID string) *LinkHandle {
+	return &LinkHandle{g: p.g, model: Link{ProjectID: p.ID(), LinkID: linkID}}
+}
+
+func (p *ProjectHandle) set(proj Project, err error) error {
+	if err != nil {
+		return err
+	}
+	p.model = proj
+	return nil
+}
+
+// NodesHandle is a handle to the nodes of a GNS3 project.
+type NodesHandle struct {
+	g         GNS3Client
+	projectID string
+}
+
+// List gets handles to all the nodes of the project.
+func (n *NodesHandle) List() ([]*NodeHandle, error) {
+	nodes, err := GetNodes(n.g, n.projectID)
+	if err != nil {
+		return nil, err
+	}
+	handles := make([]*NodeHandle, len(nodes))
+	for i, node := range nodes {
+		handles[i] = &NodeHandle{g: n.g, model: node}
+	}
+	return handles, nil
+}
+
+// Create creates a node in the project and returns its handle.
+func (n *NodesHandle) Create(c NodeCreator) (*NodeHandle, error) {
+	node, err := CreateNode(n.g, n.projectID, c)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeHandle{g: n.g, model: node}, nil
+}
+
+// NodeHandle is a handle to a GNS3 node.
+type NodeHandle struct {
+	g     GNS3Client
+	model Node
+}
+
+// ID returns the node id.
+func (n *NodeHandle) ID() string {
+	return n.model.NodeID
+}
+
+// ProjectID returns the id of the node's project.
+func (n *NodeHandle) ProjectID() string {
+	return n.model.ProjectID
+}
+
+// Model returns the last known model of the node.
+func (n *NodeHandle) Model() Node {
+	return n.model
+}
+
+// Refresh gets the current model of the node.
+func (n *NodeHandle) Refresh() error {
+	return n.set(GetNode(n.g, n.ProjectID(), n.ID()))
+}
+
+// Update updates the node.
+func (n *NodeHandle) Update(u NodeUpdater) error {
+	return n.set(UpdateNode(n.g, n.ProjectID(), n.ID(), u))
+}
+
+// Delete deletes the node.
+func (n *NodeHandle) Delete() error {
+	return DeleteNode(n.g, n.ProjectID(), n.ID())
+}
+
+// Start starts the node.
+func (n *NodeHandle) Start() error {
+	return n.set(StartNode(n.g, n.ProjectID(), n.ID()))
+}
+
+// Stop stops the node.
+func (n *NodeHandle) Stop() error {
+	return n.set(StopNode(n.g, n.ProjectID(), n.ID()))
+}
+
+// Suspend suspends the node.
+func (n *NodeHandle) Suspend() error {
+	return n.set(SuspendNode(n.g, n.ProjectID(), n.ID()))
+}
+
+// Reload reloads the node.
+func (n *NodeHandle) Reload() error {
+	return n.set(ReloadNode(n.g, n.ProjectID(), n.ID()))
+}
+
+// Isolate suspends the links connected to the node.
+func (n *NodeHandle) Isolate() error {
+	return IsolateNode(n.g, n.ProjectID(), n.ID())
+}
+
+// Unisolate resumes the links connected to the node.
+func (n *NodeHandle) Unisolate() error {
+	return UnisolateNode(n.g, n.ProjectID(), n.ID())
+}
+
+// Duplicate duplicates the node at the specified position and returns the handle of the copy.
+func (n *NodeHandle) Duplicate(x, y, z int) (*NodeHandle, error) {
+	node, err := DuplicateNode(n.g, n.ProjectID(), n.ID(), x, y, z)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeHandle{g: n.g, model: node}, nil
+}
+
+// GetFile reads a file in the node's directory.
+func (n *NodeHandle) GetFile(filepath string) ([]byte, error) {
+	return ReadNodeFile(n.g, n.ProjectID(), n.ID(), filepath)
+}
+
+// PutFile writes a file in the node's directory.
+func (n *NodeHandle) PutFile(filepath string, data []byte) error {
+	return WriteNodeFile(n.g, n.ProjectID(), n.ID(), filepath, data)
+}
+
+// GetFileTo streams a file in the node's directory to w.
+func (n *NodeHandle) GetFileTo(filepath string, w io.Writer, progress ProgressFunc) error {
+	return ReadNodeFileTo(n.g, n.ProjectID(), n.ID(), filepath, w, progress)
+}
+
+// PutFileFrom streams r to a file in the node's directory.
+func (n *NodeHandle) PutFileFrom(filepath string, r io.Reader, progress ProgressFunc) error {
+	return WriteNodeFileFrom(n.g, n.ProjectID(), n.ID(), filepath, r, progress)
+}
+
+// Links gets handles to the links connected to the node.
+func (n *NodeHandle) Links() ([]*LinkHandle, error) {
+	links, err := GetNodeLinks(n.g, n.ProjectID(), n.ID())
+	if err != nil {
+		return nil, err
+	}
+	return linkHandles(n.g, links), nil
+}
+
+func (n *NodeHandle) set(node Node, err error) error {
+	if err != nil {
+		return err
+	}
+	n.model = node
+	return nil
+}
+
+// LinksHandle is a handle to the links of a GNS3 project.
+type LinksHandle struct {
+	g         GNS3Client
+	projectID string
+}
+
+// List gets handles to all the links of the project.
+func (l *LinksHandle) List() ([]*LinkHandle, error) {
+	links, err := GetLinks(l.g, l.projectID)
+	if err != nil {
+		return nil, err
+	}
+	return linkHandles(l.g, links), nil
+}
+
+// Create creates a link in the project and returns its handle.
+func (l *LinksHandle) Create(c LinkCreator) (*LinkHandle, error) {
+	link, err := CreateLink(l.g, l.projectID, c)
+	if err != nil {
+		return nil, err
+	}
+	return &LinkHandle{g: l.g, model: link}, nil
+}
+
+// LinkHandle is a handle to a GNS3 link.
+type LinkHandle struct {
+	g     GNS3Client
+	model Link
+}
+
+// ID returns the link id.
+func (l *LinkHandle) ID() string {
+	return l.model.LinkID
+}
+
+// ProjectID returns the id of the link's project.
+func (l *LinkHandle) ProjectID() string {
+	return l.model.ProjectID
+}
+
+// Model returns the last known model of the link.
+func (l *LinkHandle) Model() Link {
+	return l.model
+}
+
+// Refresh gets the current model of the link.
+func (l *LinkHandle) Refresh() error {
+	return l.set(GetLink(l.g, l.ProjectID(), l.ID()))
+}
+
+// Update updates the link.
+func (l *LinkHandle) Update(u LinkUpdater) error {
+	return l.set(UpdateLink(l.g, l.ProjectID(), l.ID(), u))
+}
+
+// Delete deletes the link.
+func (l *LinkHandle) Delete() error {
+	return DeleteLink(l.g, l.ProjectID(), l.ID())
+}
+
+// StartCapture starts a packet capture on the link with the default options.
+func (l *LinkHandle) StartCapture() error {
+	return l.set(StartLinkCapture(l.g, l.ProjectID(), l.ID(), LinkCapture{}))
+}
+
+// StopCapture stops the packet capture on the link.
+func (l *LinkHandle) StopCapture() error {
+	return l.set(StopLinkCapture(l.g, l.ProjectID(), l.ID()))
+}
+
+func (l *LinkHandle) set(link Link, err error) error {
+	if err != nil {
+		return err
+	}
+	l.model = link
+	return nil
+}
+
+func linkHandles(g GNS3Client, links []Link) []*LinkHandle {
+	handles := make([]*LinkHandle, len(links))
+	for i, link := range links {
+		handles[i] = &LinkHandle{g: g, model: link}
+	}
+	return handles
+}
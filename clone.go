@@ -0,0 +1,59 @@
+package gons3
+
+import "fmt"
+
+// CloneOptions models how CloneNodes names and places the copies of a node.
+type CloneOptions struct {
+	// Count is the number of copies.
+	Count int
+	// NameFormat renames the copies with fmt.Sprintf and the copy's number
+	// starting at FirstIndex, such as "leaf%d". The names chosen by the
+	// server are kept when NameFormat is empty.
+	NameFormat string
+	FirstIndex int
+	// Columns is the number of copies per row of the grid, 8 by default.
+	Columns int
+	// SpacingX and SpacingY are the distances between the copies, 100 by default.
+	SpacingX int
+	SpacingY int
+}
+
+// CloneNodes duplicates a stopped node Count times and places the copies on a
+// grid below the original. The copies created before an error are returned
+// with the error, including a copy that could not be renamed.
+func CloneNodes(g GNS3Client, projectID, nodeID string, opts CloneOptions) ([]Node, error) {
+	source, err := GetNode(g, projectID, nodeID)
+	if err != nil {
+		return []Node{}, err
+	}
+	if opts.Columns <= 0 {
+		opts.Columns = 8
+	}
+	if opts.SpacingX == 0 {
+		opts.SpacingX = 100
+	}
+	if opts.SpacingY == 0 {
+		opts.SpacingY = 100
+	}
+
+	nodes := []Node{}
+	for i := 0; i < opts.Count; i++ {
+		x := source.X + (i%opts.Columns)*opts.SpacingX
+		y := source.Y + (i/opts.Columns+1)*opts.SpacingY
+		node, err := DuplicateNode(g, projectID, nodeID, x, y, source.Z)
+		if err != nil {
+			return nodes, err
+		}
+		// The copy is returned with the name chosen by the server if the rename fails
+		nodes = append(nodes, node)
+		if opts.NameFormat != "" {
+			u := NodeUpdater{}
+			u.SetName(fmt.Sprintf(opts.NameFormat, opts.FirstIndex+i))
+			if node, err = UpdateNode(g, projectID, node.NodeID, u); err != nil {
+				return nodes, err
+			}
+			nodes[i] = node
+		}
+	}
+	return nodes, nil
+}
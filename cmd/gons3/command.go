@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"gons3"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const usage = `usage:
+  gons3 [flags] project list
+  gons3 [flags] project create NAME
+  gons3 [flags] project open|close|delete PROJECT
+  gons3 [flags] project export PROJECT [FILE]
+  gons3 [flags] project import NAME [FILE]
+  gons3 [flags] node start|stop PROJECT [NODE...]
+  gons3 [flags] file get PROJECT PATH [FILE]
+  gons3 [flags] file put PROJECT PATH [FILE]
+flags:
+  --server URL        GNS3 server URL
+  --user USER[:PASS]  basic authentication user
+  --output FORMAT     output format, table or json
+`
+
+// Exit statuses.
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitNotFound
+	exitConflict
+	exitInvalid
+	exitUnauthorized
+	exitServerError
+)
+
+// command is a parsed command line.
+type command struct {
+	g      gons3.GNS3Client
+	output string
+	args   []string
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// parse parses the flags, which may appear anywhere, and the positional arguments.
+func parse(args []string, getenv func(string) string) (*command, error) {
+	fs := flag.NewFlagSet("gons3", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	server := fs.String("server", getenv("GONS3_SERVER"), "")
+	user := fs.String("user", getenv("GONS3_USER"), "")
+	output := fs.String("output", "table", "")
+
+	positional := []string{}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		// Arguments after "--" are positional, even if they look like flags
+		if parsed := len(args) - fs.NArg(); parsed > 0 && args[parsed-1] == "--" {
+			positional = append(positional, fs.Args()...)
+			break
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) < 2 {
+		return nil, errors.New("missing command")
+	}
+	if *output != "table" && *output != "json" {
+		return nil, fmt.Errorf("unknown output format %q", *output)
+	}
+
+	g, err := httpClient(*server, *user, getenv("GONS3_PASSWORD"))
+	if err != nil {
+		return nil, err
+	}
+	return &command{g: g, output: *output, args: positional}, nil
+}
+
+// httpClient creates the client from the server URL and user.
+func httpClient(server, user, password string) (gons3.GNS3HTTPClient, error) {
+	g := gons3.GNS3HTTPClient{}
+	if server != "" {
+		u, err := url.Parse(server)
+		if err != nil || u.Host == "" {
+			return g, fmt.Errorf("invalid server URL %q", server)
+		}
+		g.Scheme = u.Scheme
+		g.Hostname = u.Hostname()
+		if u.Port() != "" {
+			if g.Port, err = strconv.Atoi(u.Port()); err != nil {
+				return g, fmt.Errorf("invalid server URL %q", server)
+			}
+		}
+	}
+	if i := strings.Index(user, ":"); i >= 0 {
+		user, password = user[:i], user[i+1:]
+	}
+	g.Username, g.Password = user, password
+	return g, nil
+}
+
+// usageError reports a command with the wrong arguments.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+func (c *command) exec() error {
+	group, action, args := c.args[0], c.args[1], c.args[2:]
+	switch group + " " + action {
+	case "project list":
+		return c.projectList(args)
+	case "project create":
+		return c.projectCreate(args)
+	case "project open", "project close", "project delete":
+		return c.projectAction(action, args)
+	case "project export":
+		return c.projectExport(args)
+	case "project import":
+		return c.projectImport(args)
+	case "node start", "node stop":
+		return c.nodeAction(action, args)
+	case "file get":
+		return c.fileGet(args)
+	case "file put":
+		return c.filePut(args)
+	}
+	return usageError("unknown command " + group + " " + action)
+}
+
+func checkArgs(args []string, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return usageError("wrong number of arguments")
+	}
+	return nil
+}
+
+// exitCode maps an error to the exit status.
+func exitCode(err error) int {
+	if _, ok := err.(usageError); ok {
+		return exitUsage
+	}
+	var serverErr gons3.ServerError
+	if errors.As(err, &serverErr) {
+		switch code := serverErr.GetStatusCode(); {
+		case code == 404:
+			return exitNotFound
+		case code == 409:
+			return exitConflict
+		case code == 400 || code == 422:
+			return exitInvalid
+		case code == 401 || code == 403:
+			return exitUnauthorized
+		default:
+			return exitServerError
+		}
+	}
+	if errors.Is(err, gons3.ErrNotFound) {
+		return exitNotFound
+	}
+	return exitError
+}
+
+// openInput opens the file to read, or stdin if no file or "-" is specified.
+func (c *command) openInput(args []string, i int) (io.ReadCloser, error) {
+	if len(args) <= i || args[i] == "-" {
+		return ioutil.NopCloser(c.stdin), nil
+	}
+	return os.Open(args[i])
+}
+
+// writeOutput streams the output of write to the file, or stdout if no file
+// or "-" is specified. The file is removed if write fails.
+func (c *command) writeOutput(args []string, i int, write func(w io.Writer) error) error {
+	if len(args) <= i || args[i] == "-" {
+		return write(c.stdout)
+	}
+	f, err := os.Create(args[i])
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(args[i])
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"gons3"
+	"io"
+	"text/tabwriter"
+)
+
+func (c *command) projectList(args []string) error {
+	if err := checkArgs(args, 0, 0); err != nil {
+		return err
+	}
+	projects, err := gons3.GetProjects(c.g)
+	if err != nil {
+		return err
+	}
+	return c.printProjects(projects)
+}
+
+func (c *command) projectCreate(args []string) error {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	p := gons3.ProjectCreator{}
+	p.SetName(args[0])
+	proj, err := gons3.CreateProject(c.g, p)
+	if err != nil {
+		return err
+	}
+	return c.printProjects([]gons3.Project{proj})
+}
+
+func (c *command) projectAction(action string, args []string) error {
+	if err := checkArgs(args, 1, 1); err != nil {
+		return err
+	}
+	proj, err := c.findProject(args[0])
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "open":
+		proj, err = gons3.OpenProject(c.g, proj.ProjectID)
+	case "close":
+		proj, err = gons3.CloseProject(c.g, proj.ProjectID)
+	case "delete":
+		return gons3.DeleteProject(c.g, proj.ProjectID)
+	}
+	if err != nil {
+		return err
+	}
+	return c.printProjects([]gons3.Project{proj})
+}
+
+func (c *command) projectExport(args []string) error {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	proj, err := c.findProject(args[0])
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(args, 1, func(w io.Writer) error {
+		return gons3.ExportProjectTo(c.g, proj.ProjectID, w, nil)
+	})
+}
+
+func (c *command) projectImport(args []string) error {
+	if err := checkArgs(args, 1, 2); err != nil {
+		return err
+	}
+	r, err := c.openInput(args, 1)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	proj, err := gons3.ImportProjectFrom(c.g, newID(), args[0], r, nil)
+	if err != nil {
+		return err
+	}
+	return c.printProjects([]gons3.Project{proj})
+}
+
+// nodeAction starts or stops the specified nodes, or all the nodes of the project.
+func (c *command) nodeAction(action string, args []string) error {
+	if err := checkArgs(args, 1, -1); err != nil {
+		return err
+	}
+	proj, err := c.findProject(args[0])
+	if err != nil {
+		return err
+	}
+	nodes, err := gons3.GetNodes(c.g, proj.ProjectID)
+	if err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		selected := []gons3.Node{}
+		for _, ref := range args[1:] {
+			node, err := findNode(nodes, ref)
+			if err != nil {
+				return err
+			}
+			selected = append(selected, node)
+		}
+		nodes = selected
+	}
+
+	for i, node := range nodes {
+		switch action {
+		case "start":
+			node, err = gons3.StartNode(c.g, proj.ProjectID, node.NodeID)
+		case "stop":
+			node, err = gons3.StopNode(c.g, proj.ProjectID, node.NodeID)
+		}
+		if err != nil {
+			return err
+		}
+		nodes[i] = node
+	}
+	return c.printNodes(nodes)
+}
+
+func (c *command) fileGet(args []string) error {
+	if err := checkArgs(args, 2, 3); err != nil {
+		return err
+	}
+	proj, err := c.findProject(args[0])
+	if err != nil {
+		return err
+	}
+	return c.writeOutput(args, 2, func(w io.Writer) error {
+		return gons3.ReadProjectFileTo(c.g, proj.ProjectID, args[1], w, nil)
+	})
+}
+
+func (c *command) filePut(args []string) error {
+	if err := checkArgs(args, 2, 3); err != nil {
+		return err
+	}
+	proj, err := c.findProject(args[0])
+	if err != nil {
+		return err
+	}
+	r, err := c.openInput(args, 2)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return gons3.WriteProjectFileFrom(c.g, proj.ProjectID, args[1], r, nil)
+}
+
+// findProject finds a project by id or name.
+func (c *command) findProject(ref string) (gons3.Project, error) {
+	projects, err := gons3.GetProjects(c.g)
+	if err != nil {
+		return gons3.Project{}, err
+	}
+	for _, p := range projects {
+		if p.ProjectID == ref {
+			return p, nil
+		}
+	}
+	for _, p := range projects {
+		if p.Name == ref {
+			return p, nil
+		}
+	}
+	return gons3.Project{}, fmt.Errorf("project %q: %w", ref, gons3.ErrNotFound)
+}
+
+// findNode finds a node by id or name.
+func findNode(nodes []gons3.Node, ref string) (gons3.Node, error) {
+	for _, n := range nodes {
+		if n.NodeID == ref {
+			return n, nil
+		}
+	}
+	for _, n := range nodes {
+		if n.Name == ref {
+			return n, nil
+		}
+	}
+	return gons3.Node{}, fmt.Errorf("node %q: %w", ref, gons3.ErrNotFound)
+}
+
+func (c *command) printProjects(projects []gons3.Project) error {
+	if c.output == "json" {
+		return c.printJSON(projects)
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPROJECT ID\tSTATUS")
+	for _, p := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.ProjectID, p.Status)
+	}
+	return w.Flush()
+}
+
+func (c *command) printNodes(nodes []gons3.Node) error {
+	if c.output == "json" {
+		return c.printJSON(nodes)
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tNODE ID\tTYPE\tSTATUS\tCONSOLE")
+	for _, n := range nodes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", n.Name, n.NodeID, n.NodeType, n.Status, n.Console)
+	}
+	return w.Flush()
+}
+
+func (c *command) printJSON(v interface{}) error {
+	e := json.NewEncoder(c.stdout)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+// newID generates a random UUID for imported projects.
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,53 @@
+// Command gons3 manages GNS3 projects, nodes and files from the command line.
+//
+// Usage:
+//
+//	gons3 [flags] project list
+//	gons3 [flags] project create NAME
+//	gons3 [flags] project open|close|delete PROJECT
+//	gons3 [flags] project export PROJECT [FILE]
+//	gons3 [flags] project import NAME [FILE]
+//	gons3 [flags] node start|stop PROJECT [NODE...]
+//	gons3 [flags] file get PROJECT PATH [FILE]
+//	gons3 [flags] file put PROJECT PATH [FILE]
+//
+// Projects and nodes are referenced by name or id. Missing files read from
+// stdin or write to stdout. The flags are:
+//
+//	--server URL       GNS3 server URL (default $GONS3_SERVER or http://127.0.0.1:3080)
+//	--user USER[:PASS] basic authentication user (default $GONS3_USER, password from $GONS3_PASSWORD)
+//	--output FORMAT    output format, table or json (default table)
+//
+// The exit status is 0 on success, 1 on other errors, 2 on usage errors,
+// 3 when a resource is not found, 4 on conflicts, 5 when the server rejects
+// the request as invalid, 6 when authentication fails and 7 on server errors.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c, err := parse(args, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "gons3: %v\n%s", err, usage)
+		return exitUsage
+	}
+	c.stdin, c.stdout = stdin, stdout
+	if err := c.exec(); err != nil {
+		fmt.Fprintf(stderr, "gons3: %v\n", err)
+		code := exitCode(err)
+		if code == exitUsage {
+			fmt.Fprint(stderr, usage)
+		}
+		return code
+	}
+	return exitOK
+}
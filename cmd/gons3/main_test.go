@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"gons3"
+	"gons3/gons3test"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gons3Run(t *testing.T, s *gons3test.Server, stdin string, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"--server", s.URL}, args...)
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	if code != exitOK && stderr.Len() == 0 {
+		t.Errorf("%v: Expected an error message for exit status %v", args, code)
+	}
+	return stdout.String(), code
+}
+
+func TestProjectCommands(t *testing.T) {
+	s := gons3test.NewServer()
+	defer s.Close()
+
+	out, code := gons3Run(t, s, "", "project", "create", "TestProjectCommands")
+	if code != exitOK || !strings.Contains(out, "TestProjectCommands") {
+		t.Fatalf("Expected created project, got %v: %v", code, out)
+	}
+	if _, code := gons3Run(t, s, "", "project", "create", "TestProjectCommands"); code != exitConflict {
+		t.Errorf("Expected exit status: %v, got %v", exitConflict, code)
+	}
+
+	out, code = gons3Run(t, s, "", "project", "list", "--output", "json")
+	projects := []gons3.Project{}
+	if err := json.Unmarshal([]byte(out), &projects); err != nil || code != exitOK {
+		t.Fatalf("Expected JSON project list, got %v: %v", code, out)
+	}
+	if len(projects) != 1 || projects[0].Name != "TestProjectCommands" {
+		t.Errorf("Expected project TestProjectCommands, got %+v", projects)
+	}
+
+	out, code = gons3Run(t, s, "", "project", "close", projects[0].ProjectID)
+	if code != exitOK || !strings.Contains(out, "closed") {
+		t.Errorf("Expected closed project, got %v: %v", code, out)
+	}
+
+	if _, code := gons3Run(t, s, "config", "file", "put", "TestProjectCommands", "config.txt"); code != exitOK {
+		t.Errorf("Expected exit status: %v, got %v", exitOK, code)
+	}
+	out, code = gons3Run(t, s, "", "file", "get", "TestProjectCommands", "config.txt")
+	if code != exitOK || out != "config" {
+		t.Errorf("Expected file content: %v, got %v: %v", "config", code, out)
+	}
+	if _, code := gons3Run(t, s, "dashes", "file", "put", "TestProjectCommands", "--", "--output"); code != exitOK {
+		t.Errorf("Expected exit status: %v, got %v", exitOK, code)
+	}
+	out, code = gons3Run(t, s, "", "file", "get", "--", "TestProjectCommands", "--output")
+	if code != exitOK || out != "dashes" {
+		t.Errorf("Expected file content: %v, got %v: %v", "dashes", code, out)
+	}
+	if _, code := gons3Run(t, s, "", "file", "get", "TestProjectCommands", "missing.txt"); code != exitNotFound {
+		t.Errorf("Expected exit status: %v, got %v", exitNotFound, code)
+	}
+
+	dir, err := ioutil.TempDir("", "gons3")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "project.gns3project")
+	if _, code := gons3Run(t, s, "", "project", "export", "TestProjectCommands", archive); code != exitOK {
+		t.Fatalf("Expected exit status: %v, got %v", exitOK, code)
+	}
+	if _, code := gons3Run(t, s, "", "project", "import", "TestProjectCommandsCopy", archive); code != exitOK {
+		t.Fatalf("Expected exit status: %v, got %v", exitOK, code)
+	}
+	out, code = gons3Run(t, s, "", "file", "get", "TestProjectCommandsCopy", "config.txt")
+	if code != exitOK || out != "config" {
+		t.Errorf("Expected imported file content: %v, got %v: %v", "config", code, out)
+	}
+
+	for _, name := range []string{"TestProjectCommands", "TestProjectCommandsCopy"} {
+		if _, code := gons3Run(t, s, "", "project", "delete", name); code != exitOK {
+			t.Errorf("Expected exit status: %v, got %v", exitOK, code)
+		}
+	}
+	if _, code := gons3Run(t, s, "", "project", "open", "TestProjectCommands"); code != exitNotFound {
+		t.Errorf("Expected exit status: %v, got %v", exitNotFound, code)
+	}
+}
+
+func TestNodeCommands(t *testing.T) {
+	s := gons3test.NewServer()
+	defer s.Close()
+	g := s.Client()
+
+	p := gons3.ProjectCreator{}
+	p.SetName("TestNodeCommands")
+	proj, err := gons3.CreateProject(g, p)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	for _, name := range []string{"PC1", "PC2"} {
+		n := gons3.NodeCreator{}
+		n.SetName(name)
+		n.SetNodeType("vpcs")
+		n.SetComputeID("local")
+		if _, err := gons3.CreateNode(g, proj.ProjectID, n); err != nil {
+			t.Fatalf("Error creating node: %v", err)
+		}
+	}
+
+	out, code := gons3Run(t, s, "", "node", "start", "TestNodeCommands", "PC1")
+	if code != exitOK || !strings.Contains(out, "started") || strings.Contains(out, "PC2") {
+		t.Errorf("Expected PC1 started, got %v: %v", code, out)
+	}
+	out, code = gons3Run(t, s, "", "node", "stop", "TestNodeCommands")
+	if code != exitOK || strings.Count(out, "stopped") != 2 {
+		t.Errorf("Expected all nodes stopped, got %v: %v", code, out)
+	}
+	if _, code := gons3Run(t, s, "", "node", "start", "TestNodeCommands", "PC3"); code != exitNotFound {
+		t.Errorf("Expected exit status: %v, got %v", exitNotFound, code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	s := gons3test.NewServer()
+	defer s.Close()
+
+	for _, args := range [][]string{
+		{},
+		{"project"},
+		{"project", "rename", "a"},
+		{"project", "create"},
+		{"project", "list", "--output", "yaml"},
+	} {
+		if _, code := gons3Run(t, s, "", args...); code != exitUsage {
+			t.Errorf("%v: Expected exit status: %v, got %v", args, exitUsage, code)
+		}
+	}
+}
+
+func TestHTTPClient(t *testing.T) {
+	g, err := httpClient("https://gns3.example.com:8443", "admin:secret", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.GetSchemeAuthority() != "https://gns3.example.com:8443" || g.Username != "admin" || g.Password != "secret" {
+		t.Errorf("Unexpected client: %+v", g)
+	}
+	g, err = httpClient("", "admin", "env")
+	if err != nil || g.GetSchemeAuthority() != "http://127.0.0.1:3080" || g.Password != "env" {
+		t.Errorf("Unexpected client: %+v, %v", g, err)
+	}
+	if _, err := httpClient("gns3", "", ""); err == nil {
+		t.Errorf("Expected an error for an invalid server URL")
+	}
+}
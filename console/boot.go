@@ -0,0 +1,82 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"regexp"
+	"time"
+)
+
+// DefaultBootPatterns match the consoles of booted routers, switches and hosts.
+var DefaultBootPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`Press RETURN to get started`),
+	regexp.MustCompile(`(?i)(?:login|username):\s*$`),
+	regexp.MustCompile(`[\w.()-]+[>#$]\s*$`),
+}
+
+// BootOptions configures WaitForBoot.
+type BootOptions struct {
+	// Patterns match the output of a booted node. The default is DefaultBootPatterns.
+	Patterns []*regexp.Regexp
+	// NudgeInterval is the time without a match after which a carriage
+	// return is sent to make the node print its prompt. Zero disables it.
+	NudgeInterval time.Duration
+	// RetryInterval is the interval between connection attempts while the
+	// console is not listening yet. The default is one second.
+	RetryInterval time.Duration
+}
+
+// WaitForBoot connects to the console of a node and waits until its output
+// matches a boot pattern or the context is done. It returns the console
+// output received while booting, even on error.
+func WaitForBoot(ctx context.Context, g gons3.GNS3Client, node gons3.Node, opts BootOptions) (string, error) {
+	patterns := opts.Patterns
+	if len(patterns) == 0 {
+		patterns = DefaultBootPatterns
+	}
+	retry := opts.RetryInterval
+	if retry == 0 {
+		retry = time.Second
+	}
+
+	conn, err := dialRetry(ctx, g, node, retry)
+	if err != nil {
+		return "", err
+	}
+	e := NewExpecter(conn)
+	defer e.Close()
+
+	for {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if opts.NudgeInterval > 0 {
+			waitCtx, cancel = context.WithTimeout(ctx, opts.NudgeInterval)
+		}
+		_, _, err := e.ExpectContext(waitCtx, patterns...)
+		cancel()
+		if err == nil || ctx.Err() != nil || !errors.Is(err, ErrTimeout) {
+			return e.Transcript(), err
+		}
+		if err := e.Send("\r"); err != nil {
+			return e.Transcript(), err
+		}
+	}
+}
+
+// dialRetry dials the console until it accepts the connection or the context is done.
+func dialRetry(ctx context.Context, g gons3.GNS3Client, node gons3.Node, retry time.Duration) (*Conn, error) {
+	for {
+		conn, err := DialContext(ctx, g, node)
+		if err == nil || err == ErrNoConsole || err == ErrUnsupportedConsoleType {
+			return conn, err
+		}
+
+		t := time.NewTimer(retry)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, err
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package console_test
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"gons3/console"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForBoot(t *testing.T) {
+	// Reserve a port, then start listening on it later like a booting node.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return
+		}
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "Booting IOS...\r\n")
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(conn, "Press RETURN to get started!\r\n")
+		time.Sleep(time.Second)
+	}()
+
+	node := gons3.Node{ConsoleHost: "127.0.0.1", Console: l.Addr().(*net.TCPAddr).Port, ConsoleType: "telnet"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	transcript, err := console.WaitForBoot(ctx, gons3.GNS3HTTPClient{}, node, console.BootOptions{RetryInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error waiting for boot: %v", err)
+	}
+	if !strings.Contains(transcript, "Booting IOS") || !strings.Contains(transcript, "Press RETURN") {
+		t.Errorf("Unexpected transcript: %q", transcript)
+	}
+}
+
+func TestWaitForBootNudge(t *testing.T) {
+	node := listen(t, func(conn net.Conn) {
+		io.WriteString(conn, "System ready\r\n")
+		b := make([]byte, 1)
+		if _, err := conn.Read(b); err != nil || b[0] != '\r' {
+			return
+		}
+		io.WriteString(conn, "\r\nswitch>")
+		time.Sleep(time.Second)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	transcript, err := console.WaitForBoot(ctx, gons3.GNS3HTTPClient{}, node, console.BootOptions{NudgeInterval: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Error waiting for boot: %v", err)
+	}
+	if !strings.HasSuffix(transcript, "switch>") {
+		t.Errorf("Unexpected transcript: %q", transcript)
+	}
+}
+
+func TestWaitForBootTimeout(t *testing.T) {
+	node := listen(t, func(conn net.Conn) {
+		io.WriteString(conn, "Loading image...")
+		time.Sleep(time.Second)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	transcript, err := console.WaitForBoot(ctx, gons3.GNS3HTTPClient{}, node, console.BootOptions{})
+	if !errors.Is(err, console.ErrTimeout) {
+		t.Errorf("Expected error: %v, got %v", console.ErrTimeout, err)
+	}
+	if transcript != "Loading image..." {
+		t.Errorf("Expected transcript: %q, got %q", "Loading image...", transcript)
+	}
+
+	if _, err := console.WaitForBoot(ctx, gons3.GNS3HTTPClient{}, gons3.Node{ConsoleType: "vnc", Console: 5900}, console.BootOptions{}); err != console.ErrUnsupportedConsoleType {
+		t.Errorf("Expected error: %v, got %v", console.ErrUnsupportedConsoleType, err)
+	}
+}
@@ -0,0 +1,279 @@
+// Package console connects to the telnet consoles of GNS3 nodes.
+package console
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoConsole is returned when the node has no console port.
+var ErrNoConsole = errors.New("console: node has no console")
+
+// ErrUnsupportedConsoleType is returned for consoles other than telnet, such as vnc or spice.
+var ErrUnsupportedConsoleType = errors.New("console: unsupported console type")
+
+// DialTimeout is the timeout of Dial.
+var DialTimeout = 10 * time.Second
+
+// Telnet commands and options.
+const (
+	se   = 240
+	sb   = 250
+	will = 251
+	wont = 252
+	do   = 253
+	dont = 254
+	iac  = 255
+
+	optBinary = 0
+	optEcho   = 1
+	optSGA    = 3
+)
+
+// Parser states.
+const (
+	stateData = iota
+	stateCR
+	stateIAC
+	stateOption
+	stateSB
+	stateSBIAC
+)
+
+// Conn is a telnet connection to a node console. Reads return the console
+// output with the telnet commands removed, writes escape the data. The
+// option negotiation is answered while reading: the server may echo and
+// suppress go ahead, every other option is refused. The state of each
+// option is tracked as in RFC 1143, so requests that do not change it are
+// not answered and negotiation cannot loop.
+type Conn struct {
+	conn net.Conn
+
+	rmu   sync.Mutex
+	buf   []byte
+	state int
+	cmd   byte
+	// remote and local are the options enabled on the server and on our side.
+	remote [256]bool
+	local  [256]bool
+
+	wmu sync.Mutex
+}
+
+// NewConn creates a telnet Conn on an established connection.
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn}
+}
+
+// Dial connects to the console of a node. See DialContext.
+func Dial(g gons3.GNS3Client, node gons3.Node) (*Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DialTimeout)
+	defer cancel()
+	return DialContext(ctx, g, node)
+}
+
+// DialContext connects to the telnet console of a node. When the node's
+// console host is a wildcard address, the host of the GNS3 server is used.
+func DialContext(ctx context.Context, g gons3.GNS3Client, node gons3.Node) (*Conn, error) {
+	addr, err := Address(g, node)
+	if err != nil {
+		return nil, err
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(conn), nil
+}
+
+// Address returns the host:port address of the node's telnet console.
+func Address(g gons3.GNS3Client, node gons3.Node) (string, error) {
+	if node.ConsoleType != "" && node.ConsoleType != "telnet" {
+		return "", ErrUnsupportedConsoleType
+	}
+	if node.Console == 0 {
+		return "", ErrNoConsole
+	}
+
+	host := node.ConsoleHost
+	switch host {
+	case "", "0.0.0.0", "::", "0:0:0:0:0:0:0:0":
+		u, err := url.Parse(g.GetSchemeAuthority())
+		if err != nil {
+			return "", err
+		}
+		host = u.Hostname()
+	}
+	return net.JoinHostPort(host, strconv.Itoa(node.Console)), nil
+}
+
+// Read reads console output.
+func (c *Conn) Read(p []byte) (int, error) {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+
+	if len(c.buf) < len(p) {
+		c.buf = make([]byte, len(p))
+	}
+	for {
+		n, err := c.conn.Read(c.buf[:len(p)])
+		m, werr := c.filter(c.buf[:n], p)
+		if werr != nil {
+			return m, werr
+		}
+		if m > 0 || err != nil {
+			return m, err
+		}
+	}
+}
+
+// filter copies the data bytes of in to out and answers the telnet commands.
+func (c *Conn) filter(in, out []byte) (int, error) {
+	n := 0
+	for _, b := range in {
+		switch c.state {
+		case stateData, stateCR:
+			if b == iac {
+				c.state = stateIAC
+				continue
+			}
+			if c.state == stateCR && b == 0 {
+				c.state = stateData
+				continue
+			}
+			c.state = stateData
+			if b == '\r' {
+				c.state = stateCR
+			}
+			out[n] = b
+			n++
+		case stateIAC:
+			switch b {
+			case iac:
+				out[n] = b
+				n++
+				c.state = stateData
+			case will, wont, do, dont:
+				c.cmd = b
+				c.state = stateOption
+			case sb:
+				c.state = stateSB
+			default:
+				c.state = stateData
+			}
+		case stateOption:
+			c.state = stateData
+			if err := c.answer(c.cmd, b); err != nil {
+				return n, err
+			}
+		case stateSB:
+			if b == iac {
+				c.state = stateSBIAC
+			}
+		case stateSBIAC:
+			c.state = stateSB
+			if b == se {
+				c.state = stateData
+			}
+		}
+	}
+	return n, nil
+}
+
+// answer replies to an option negotiation that changes the state of the option.
+func (c *Conn) answer(cmd, opt byte) error {
+	var reply byte
+	switch cmd {
+	case will:
+		if c.remote[opt] {
+			return nil
+		}
+		reply = dont
+		if opt == optEcho || opt == optSGA || opt == optBinary {
+			c.remote[opt] = true
+			reply = do
+		}
+	case wont:
+		if !c.remote[opt] {
+			return nil
+		}
+		c.remote[opt] = false
+		reply = dont
+	case do:
+		if c.local[opt] {
+			return nil
+		}
+		reply = wont
+		if opt == optSGA || opt == optBinary {
+			c.local[opt] = true
+			reply = will
+		}
+	case dont:
+		if !c.local[opt] {
+			return nil
+		}
+		c.local[opt] = false
+		reply = wont
+	default:
+		return nil
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, err := c.conn.Write([]byte{iac, reply, opt})
+	return err
+}
+
+// Write writes to the console, escaping the telnet IAC byte.
+func (c *Conn) Write(p []byte) (int, error) {
+	escaped := make([]byte, 0, len(p))
+	for _, b := range p {
+		if b == iac {
+			escaped = append(escaped, iac)
+		}
+		escaped = append(escaped, b)
+	}
+
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if _, err := c.conn.Write(escaped); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// LocalAddr returns the local network address.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the address of the console.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
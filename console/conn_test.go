@@ -0,0 +1,153 @@
+package console_test
+
+import (
+	"bytes"
+	"gons3"
+	"gons3/console"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+// listen starts a console server that runs serve on the first connection.
+func listen(t *testing.T, serve func(conn net.Conn)) gons3.Node {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %v", err)
+	}
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(conn)
+	}()
+	port := l.Addr().(*net.TCPAddr).Port
+	return gons3.Node{Name: "R1", ConsoleHost: "0.0.0.0", Console: port, ConsoleType: "telnet"}
+}
+
+func TestConn(t *testing.T) {
+	replies := make(chan []byte, 1)
+	written := make(chan []byte, 1)
+	node := listen(t, func(conn net.Conn) {
+		conn.Write([]byte{255, 251, 1, 255, 253, 31, 'h', 'e', 'l', 255, 255, 'l', 'o', '\r', 0})
+		conn.Write([]byte{255, 250, 24, 1, 255, 240, '!'})
+		reply := make([]byte, 6)
+		io.ReadFull(conn, reply)
+		replies <- reply
+		data := make([]byte, 4)
+		io.ReadFull(conn, data)
+		written <- data
+	})
+
+	c, err := console.Dial(gons3.GNS3HTTPClient{}, node)
+	if err != nil {
+		t.Fatalf("Error dialing console: %v", err)
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(5 * time.Second))
+
+	out := make([]byte, 0, 16)
+	buf := make([]byte, 4)
+	for len(out) < 8 {
+		n, err := c.Read(buf)
+		if err != nil {
+			t.Fatalf("Error reading console: %v", err)
+		}
+		out = append(out, buf[:n]...)
+	}
+	if expected := []byte("hel\xfflo\r!"); !bytes.Equal(out, expected) {
+		t.Errorf("Expected output: %q, got %q", expected, out)
+	}
+	if reply, expected := <-replies, []byte{255, 253, 1, 255, 252, 31}; !bytes.Equal(reply, expected) {
+		t.Errorf("Expected negotiation: %v, got %v", expected, reply)
+	}
+
+	if _, err := c.Write([]byte("a\xffb")); err != nil {
+		t.Fatalf("Error writing console: %v", err)
+	}
+	if data, expected := <-written, []byte("a\xff\xffb"); !bytes.Equal(data, expected) {
+		t.Errorf("Expected written data: %q, got %q", expected, data)
+	}
+}
+
+func TestConnNegotiation(t *testing.T) {
+	replies := make(chan []byte, 1)
+	node := listen(t, func(conn net.Conn) {
+		conn.Write([]byte{255, 251, 1, 255, 251, 1, 255, 252, 31, 255, 253, 3, 255, 253, 3})
+		conn.Write([]byte{255, 254, 3, 255, 254, 24, 255, 252, 1, 'o', 'k'})
+		reply := make([]byte, 12)
+		io.ReadFull(conn, reply)
+		replies <- reply
+	})
+
+	c, err := console.Dial(gons3.GNS3HTTPClient{}, node)
+	if err != nil {
+		t.Fatalf("Error dialing console: %v", err)
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(5 * time.Second))
+
+	out := make([]byte, 0, 2)
+	buf := make([]byte, 2)
+	for len(out) < 2 {
+		n, err := c.Read(buf)
+		if err != nil {
+			t.Fatalf("Error reading console: %v", err)
+		}
+		out = append(out, buf[:n]...)
+	}
+	if string(out) != "ok" {
+		t.Errorf("Expected output: %q, got %q", "ok", out)
+	}
+	// Repeated requests and requests for the current state are not answered.
+	expected := []byte{255, 253, 1, 255, 251, 3, 255, 252, 3, 255, 254, 1}
+	if reply := <-replies; !bytes.Equal(reply, expected) {
+		t.Errorf("Expected negotiation: %v, got %v", expected, reply)
+	}
+}
+
+func TestConnEOF(t *testing.T) {
+	node := listen(t, func(conn net.Conn) {
+		conn.Write([]byte("bye"))
+	})
+	c, err := console.Dial(gons3.GNS3HTTPClient{}, node)
+	if err != nil {
+		t.Fatalf("Error dialing console: %v", err)
+	}
+	defer c.Close()
+	data, err := ioutil.ReadAll(c)
+	if err != nil || string(data) != "bye" {
+		t.Errorf("Expected output: %q, got %q, %v", "bye", data, err)
+	}
+}
+
+func TestAddress(t *testing.T) {
+	g := gons3.GNS3HTTPClient{Hostname: "gns3.example.com"}
+	tests := []struct {
+		name     string
+		node     gons3.Node
+		expected string
+		err      error
+	}{
+		{"ConsoleHost", gons3.Node{ConsoleHost: "10.0.0.1", Console: 5000, ConsoleType: "telnet"}, "10.0.0.1:5000", nil},
+		{"Wildcard", gons3.Node{ConsoleHost: "0.0.0.0", Console: 5001, ConsoleType: "telnet"}, "gns3.example.com:5001", nil},
+		{"IPv6", gons3.Node{ConsoleHost: "::1", Console: 5002}, "[::1]:5002", nil},
+		{"NoConsole", gons3.Node{ConsoleType: "none"}, "", console.ErrUnsupportedConsoleType},
+		{"NoPort", gons3.Node{ConsoleType: "telnet"}, "", console.ErrNoConsole},
+		{"VNC", gons3.Node{Console: 5900, ConsoleType: "vnc"}, "", console.ErrUnsupportedConsoleType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := console.Address(g, tt.node)
+			if addr != tt.expected || err != tt.err {
+				t.Errorf("Expected %v, %v, got %v, %v", tt.expected, tt.err, addr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package console
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTimeout is returned when the expected output was not received in time.
+var ErrTimeout = errors.New("console: timeout")
+
+// DefaultPager matches the pagers of Cisco, Juniper and Arista devices.
+var DefaultPager = regexp.MustCompile(`(?:\s?--More--\s?|---\(more(?: \d+%)?\)---|<--- More --->)`)
+
+// ExpectError is returned when the expected output was not received. It
+// wraps ErrTimeout, io.EOF or the read error.
+type ExpectError struct {
+	Err      error
+	Patterns []string
+	Output   string // Output received since the last match.
+}
+
+func (e *ExpectError) Error() string {
+	return "console: expecting " + strings.Join(e.Patterns, " or ") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *ExpectError) Unwrap() error {
+	return e.Err
+}
+
+// Match is the output matched by Expect.
+type Match struct {
+	Before string   // Output received before the match.
+	Text   string   // Matched output.
+	Groups []string // Submatches of the pattern.
+}
+
+// Expecter automates a console by sending input and waiting for the output
+// to match patterns. Pager prompts are answered while waiting and removed
+// from the output. All the output is kept in the transcript.
+type Expecter struct {
+	rw io.ReadWriter
+
+	// Timeout is the timeout of Expect. The default is 30 seconds.
+	Timeout time.Duration
+	// Pager matches the pager prompts, nil disables paging. The default is DefaultPager.
+	Pager *regexp.Regexp
+	// PagerReply is sent to show the next page. The default is a space.
+	PagerReply string
+	// LineEnding terminates the lines sent by SendLine. The default is "\r".
+	LineEnding string
+	// Log receives the output as it is read, if set.
+	Log io.Writer
+
+	chunks chan []byte
+	done   chan struct{}
+	once   sync.Once
+	err    error
+	buf    []byte
+
+	mu         sync.Mutex
+	transcript bytes.Buffer
+}
+
+// NewExpecter creates an Expecter on a console connection, such as a *Conn.
+func NewExpecter(rw io.ReadWriter) *Expecter {
+	e := &Expecter{
+		rw:         rw,
+		Timeout:    30 * time.Second,
+		Pager:      DefaultPager,
+		PagerReply: " ",
+		LineEnding: "\r",
+		chunks:     make(chan []byte),
+		done:       make(chan struct{}),
+	}
+	go e.read()
+	return e
+}
+
+// read reads the output until the connection fails.
+func (e *Expecter) read() {
+	defer close(e.chunks)
+	for {
+		buf := make([]byte, 4096)
+		n, err := e.rw.Read(buf)
+		if n > 0 {
+			e.mu.Lock()
+			e.transcript.Write(buf[:n])
+			e.mu.Unlock()
+			select {
+			case e.chunks <- buf[:n]:
+			case <-e.done:
+				e.err = io.ErrClosedPipe
+				return
+			}
+		}
+		if err != nil {
+			e.err = err
+			return
+		}
+	}
+}
+
+// Send sends text to the console.
+func (e *Expecter) Send(s string) error {
+	_, err := io.WriteString(e.rw, s)
+	return err
+}
+
+// SendLine sends a line to the console.
+func (e *Expecter) SendLine(line string) error {
+	return e.Send(line + e.LineEnding)
+}
+
+// Expect waits for the output to match the pattern within Timeout.
+func (e *Expecter) Expect(pattern *regexp.Regexp) (Match, error) {
+	_, m, err := e.ExpectAny(pattern)
+	return m, err
+}
+
+// ExpectString waits for the output to contain s within Timeout.
+func (e *Expecter) ExpectString(s string) (Match, error) {
+	return e.Expect(regexp.MustCompile(regexp.QuoteMeta(s)))
+}
+
+// ExpectAny waits for the output to match one of the patterns within
+// Timeout, returning the index of the pattern that matched first.
+func (e *Expecter) ExpectAny(patterns ...*regexp.Regexp) (int, Match, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.Timeout)
+	defer cancel()
+	return e.ExpectContext(ctx, patterns...)
+}
+
+// ExpectContext waits for the output to match one of the patterns until
+// the context is done. The output up to the end of the match is consumed.
+func (e *Expecter) ExpectContext(ctx context.Context, patterns ...*regexp.Regexp) (int, Match, error) {
+	for {
+		if err := e.page(); err != nil {
+			return -1, Match{}, err
+		}
+		if i, m, ok := e.match(patterns); ok {
+			return i, m, nil
+		}
+
+		select {
+		case chunk, ok := <-e.chunks:
+			if !ok {
+				return -1, Match{}, e.expectError(e.err, patterns)
+			}
+			e.buf = append(e.buf, chunk...)
+			if e.Log != nil {
+				e.Log.Write(chunk)
+			}
+		case <-ctx.Done():
+			err := ctx.Err()
+			if err == context.DeadlineExceeded {
+				err = ErrTimeout
+			}
+			return -1, Match{}, e.expectError(err, patterns)
+		}
+	}
+}
+
+// page answers and removes the pager prompts in the output.
+func (e *Expecter) page() error {
+	if e.Pager == nil {
+		return nil
+	}
+	for {
+		loc := e.Pager.FindIndex(e.buf)
+		if loc == nil {
+			return nil
+		}
+		e.buf = append(e.buf[:loc[0]], e.buf[loc[1]:]...)
+		if err := e.Send(e.PagerReply); err != nil {
+			return err
+		}
+	}
+}
+
+// match finds the earliest match of the patterns and consumes the output up to it.
+func (e *Expecter) match(patterns []*regexp.Regexp) (int, Match, bool) {
+	index, first := -1, []int(nil)
+	for i, p := range patterns {
+		loc := p.FindSubmatchIndex(e.buf)
+		if loc != nil && (first == nil || loc[0] < first[0]) {
+			index, first = i, loc
+		}
+	}
+	if first == nil {
+		return -1, Match{}, false
+	}
+
+	m := Match{Before: string(e.buf[:first[0]]), Text: string(e.buf[first[0]:first[1]])}
+	for i := 2; i < len(first); i += 2 {
+		group := ""
+		if first[i] >= 0 {
+			group = string(e.buf[first[i]:first[i+1]])
+		}
+		m.Groups = append(m.Groups, group)
+	}
+	e.buf = append([]byte{}, e.buf[first[1]:]...)
+	return index, m, true
+}
+
+func (e *Expecter) expectError(err error, patterns []*regexp.Regexp) error {
+	names := make([]string, len(patterns))
+	for i, p := range patterns {
+		names[i] = p.String()
+	}
+	return &ExpectError{Err: err, Patterns: names, Output: string(e.buf)}
+}
+
+// Command sends a line and waits for the prompt, returning the output of the
+// command without the echoed line.
+func (e *Expecter) Command(line string, prompt *regexp.Regexp) (string, error) {
+	if err := e.SendLine(line); err != nil {
+		return "", err
+	}
+	m, err := e.Expect(prompt)
+	if err != nil {
+		return "", err
+	}
+	output := strings.TrimLeft(m.Before, "\r\n")
+	if strings.HasPrefix(output, line) {
+		output = strings.TrimLeft(output[len(line):], "\r\n")
+	}
+	return output, nil
+}
+
+// Transcript returns all the output received from the console.
+func (e *Expecter) Transcript() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.transcript.String()
+}
+
+// Close closes the console connection if it is an io.Closer.
+func (e *Expecter) Close() error {
+	e.once.Do(func() { close(e.done) })
+	if c, ok := e.rw.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
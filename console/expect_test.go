@@ -0,0 +1,108 @@
+package console_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"gons3/console"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// device simulates a router console on the server side of a pipe.
+func device(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	io.WriteString(conn, "\r\nRouter>")
+	for {
+		line, err := r.ReadString('\r')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		io.WriteString(conn, line+"\r\n")
+		switch line {
+		case "enable":
+			io.WriteString(conn, "Router#")
+		case "show version":
+			io.WriteString(conn, "Cisco IOS Software, Version 15.6(2)T\r\n --More-- ")
+			if b, err := r.ReadByte(); err != nil || b != ' ' {
+				return
+			}
+			io.WriteString(conn, "\r          \rUptime is 5 minutes\r\nRouter#")
+		case "quit":
+			return
+		default:
+			io.WriteString(conn, "% Invalid input\r\nRouter#")
+		}
+	}
+}
+
+var prompt = regexp.MustCompile(`(\w+)[>#]$`)
+
+func TestExpecter(t *testing.T) {
+	client, server := net.Pipe()
+	go device(server)
+	e := console.NewExpecter(client)
+	defer e.Close()
+	e.Timeout = 5 * time.Second
+
+	m, err := e.Expect(prompt)
+	if err != nil {
+		t.Fatalf("Error expecting prompt: %v", err)
+	}
+	if m.Text != "Router>" || len(m.Groups) != 1 || m.Groups[0] != "Router" {
+		t.Errorf("Unexpected match: %+v", m)
+	}
+
+	if _, err := e.Command("enable", prompt); err != nil {
+		t.Fatalf("Error enabling: %v", err)
+	}
+	output, err := e.Command("show version", prompt)
+	if err != nil {
+		t.Fatalf("Error showing version: %v", err)
+	}
+	if strings.Contains(output, "More") || !strings.Contains(output, "Version 15.6") || !strings.Contains(output, "Uptime") {
+		t.Errorf("Expected paged output without pager, got %q", output)
+	}
+
+	if err := e.SendLine("bogus"); err != nil {
+		t.Fatalf("Error sending line: %v", err)
+	}
+	i, _, err := e.ExpectAny(regexp.MustCompile(`Invalid input`), prompt)
+	if err != nil || i != 0 {
+		t.Errorf("Expected the first pattern to match, got %v, %v", i, err)
+	}
+
+	e.Timeout = 50 * time.Millisecond
+	_, err = e.ExpectString("Password:")
+	var expectErr *console.ExpectError
+	if !errors.Is(err, console.ErrTimeout) || !errors.As(err, &expectErr) || !strings.Contains(expectErr.Output, "Router#") {
+		t.Errorf("Expected timeout with output, got %v", err)
+	}
+
+	e.SendLine("quit")
+	if _, err := e.ExpectString("never"); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected error: %v, got %v", io.EOF, err)
+	}
+	if transcript := e.Transcript(); !strings.HasPrefix(transcript, "\r\nRouter>enable") || !strings.Contains(transcript, "--More--") {
+		t.Errorf("Unexpected transcript: %q", transcript)
+	}
+}
+
+func TestExpectContext(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	e := console.NewExpecter(client)
+	defer e.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := e.ExpectContext(ctx, prompt); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error: %v, got %v", context.Canceled, err)
+	}
+}
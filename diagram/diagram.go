@@ -0,0 +1,183 @@
+// Package diagram renders the topology of a GNS3 project as a Graphviz DOT or
+// Mermaid diagram.
+//
+// Nodes are labeled with their name and type and colored by status. Links are
+// labeled with the names of their ports, suspended links are dashed and
+// capturing links are marked.
+package diagram
+
+import (
+	"fmt"
+	"gons3"
+	"io"
+	"strings"
+)
+
+// Diagram is the topology of a project.
+type Diagram struct {
+	Name  string
+	Nodes []gons3.Node
+	Links []gons3.Link
+}
+
+// Fetch gets the nodes and links of a project.
+func Fetch(g gons3.GNS3Client, projectID string) (*Diagram, error) {
+	proj, err := gons3.GetProject(g, projectID)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := gons3.GetNodes(g, projectID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := gons3.GetLinks(g, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &Diagram{Name: proj.Name, Nodes: nodes, Links: links}, nil
+}
+
+// DOT fetches a project and renders it as a Graphviz DOT diagram.
+func DOT(g gons3.GNS3Client, projectID string) (string, error) {
+	d, err := Fetch(g, projectID)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := d.WriteDOT(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Mermaid fetches a project and renders it as a Mermaid flowchart.
+func Mermaid(g gons3.GNS3Client, projectID string) (string, error) {
+	d, err := Fetch(g, projectID)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := d.WriteMermaid(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var dotColors = map[string]string{"started": "green", "suspended": "orange", "stopped": "red"}
+
+// WriteDOT writes the diagram as an undirected Graphviz graph.
+func (d *Diagram) WriteDOT(w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("graph %s {\n", dotQuote(d.Name))
+	ew.printf("\tnode [shape=box];\n")
+	for _, n := range d.Nodes {
+		ew.printf("\t%s [label=%s", dotQuote(n.NodeID), dotQuote(n.Name+"\n"+n.NodeType))
+		if color, ok := dotColors[n.Status]; ok {
+			ew.printf(" color=%s", color)
+		}
+		ew.printf("];\n")
+	}
+	for _, l := range d.Links {
+		if len(l.Nodes) != 2 {
+			continue
+		}
+		a, b := l.Nodes[0], l.Nodes[1]
+		ew.printf("\t%s -- %s [taillabel=%s headlabel=%s", dotQuote(a.NodeID), dotQuote(b.NodeID), dotQuote(d.portName(a)), dotQuote(d.portName(b)))
+		if l.Suspend {
+			ew.printf(" style=dashed color=gray")
+		}
+		if l.Capturing {
+			ew.printf(" label=\"capturing\"")
+		}
+		ew.printf("];\n")
+	}
+	ew.printf("}\n")
+	return ew.err
+}
+
+// WriteMermaid writes the diagram as a Mermaid flowchart.
+func (d *Diagram) WriteMermaid(w io.Writer) error {
+	ids := map[string]string{}
+	for i, n := range d.Nodes {
+		ids[n.NodeID] = fmt.Sprintf("n%d", i)
+	}
+
+	ew := &errWriter{w: w}
+	ew.printf("graph LR\n")
+	for _, n := range d.Nodes {
+		ew.printf("\t%s[%s]\n", ids[n.NodeID], mermaidQuote(n.Name+"<br/>"+n.NodeType))
+	}
+	for _, l := range d.Links {
+		if len(l.Nodes) != 2 {
+			continue
+		}
+		a, b := l.Nodes[0], l.Nodes[1]
+		label := d.portName(a) + " - " + d.portName(b)
+		if l.Capturing {
+			label += " (capturing)"
+		}
+		arrow := "---"
+		if l.Suspend {
+			arrow = "-.-"
+		}
+		ew.printf("\t%s %s|%s| %s\n", mermaidID(ids, a.NodeID), arrow, mermaidQuote(label), mermaidID(ids, b.NodeID))
+	}
+	for _, status := range []string{"started", "suspended", "stopped"} {
+		var nodes []string
+		for _, n := range d.Nodes {
+			if n.Status == status {
+				nodes = append(nodes, ids[n.NodeID])
+			}
+		}
+		if len(nodes) > 0 {
+			ew.printf("\tclassDef %s stroke:%s\n", status, dotColors[status])
+			ew.printf("\tclass %s %s\n", strings.Join(nodes, ","), status)
+		}
+	}
+	return ew.err
+}
+
+// portName returns the name of a link endpoint's port, or adapter/port if
+// the node is unknown.
+func (d *Diagram) portName(ln gons3.LinkNode) string {
+	for _, n := range d.Nodes {
+		if n.NodeID != ln.NodeID {
+			continue
+		}
+		for _, p := range n.Ports {
+			if p.AdapterNumber == ln.AdapterNumber && p.PortNumber == ln.PortNumber {
+				return p.Name
+			}
+		}
+	}
+	return fmt.Sprintf("%d/%d", ln.AdapterNumber, ln.PortNumber)
+}
+
+func mermaidID(ids map[string]string, nodeID string) string {
+	if id, ok := ids[nodeID]; ok {
+		return id
+	}
+	return "unknown"
+}
+
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + strings.Replace(s, "\n", `\n`, -1) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+}
+
+// errWriter keeps the first write error.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) printf(format string, args ...interface{}) {
+	if e.err == nil {
+		_, e.err = fmt.Fprintf(e.w, format, args...)
+	}
+}
@@ -0,0 +1,114 @@
+package diagram_test
+
+import (
+	"gons3"
+	"gons3/diagram"
+	"gons3/gons3test"
+	"strings"
+	"testing"
+)
+
+func testDiagram() *diagram.Diagram {
+	ports := []gons3.NodePort{{Name: "Ethernet0", AdapterNumber: 0, PortNumber: 0}, {Name: "Ethernet1", AdapterNumber: 0, PortNumber: 1}}
+	return &diagram.Diagram{
+		Name: `lab "1"`,
+		Nodes: []gons3.Node{
+			{NodeID: "pc1", Name: "PC1", NodeType: "vpcs", Status: "started", Ports: ports[:1]},
+			{NodeID: "pc2", Name: "PC2", NodeType: "vpcs", Status: "stopped", Ports: ports[:1]},
+			{NodeID: "sw1", Name: "SW1", NodeType: "ethernet_switch", Status: "started", Ports: ports},
+		},
+		Links: []gons3.Link{
+			{Nodes: []gons3.LinkNode{{NodeID: "pc1"}, {NodeID: "sw1"}}, Capturing: true},
+			{Nodes: []gons3.LinkNode{{NodeID: "pc2"}, {NodeID: "sw1", PortNumber: 1}}, Suspend: true},
+			{Nodes: []gons3.LinkNode{{NodeID: "sw1", AdapterNumber: 1}, {NodeID: "r1"}}},
+		},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	expected := `graph "lab \"1\"" {
+	node [shape=box];
+	"pc1" [label="PC1\nvpcs" color=green];
+	"pc2" [label="PC2\nvpcs" color=red];
+	"sw1" [label="SW1\nethernet_switch" color=green];
+	"pc1" -- "sw1" [taillabel="Ethernet0" headlabel="Ethernet0" label="capturing"];
+	"pc2" -- "sw1" [taillabel="Ethernet0" headlabel="Ethernet1" style=dashed color=gray];
+	"sw1" -- "r1" [taillabel="1/0" headlabel="0/0"];
+}
+`
+	var b strings.Builder
+	if err := testDiagram().WriteDOT(&b); err != nil {
+		t.Fatalf("Error writing diagram: %v", err)
+	}
+	if b.String() != expected {
+		t.Errorf("Expected:\n%v\ngot:\n%v", expected, b.String())
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	expected := `graph LR
+	n0["PC1<br/>vpcs"]
+	n1["PC2<br/>vpcs"]
+	n2["SW1<br/>ethernet_switch"]
+	n0 ---|"Ethernet0 - Ethernet0 (capturing)"| n2
+	n1 -.-|"Ethernet0 - Ethernet1"| n2
+	n2 ---|"1/0 - 0/0"| unknown
+	classDef started stroke:green
+	class n0,n2 started
+	classDef stopped stroke:red
+	class n1 stopped
+`
+	var b strings.Builder
+	if err := testDiagram().WriteMermaid(&b); err != nil {
+		t.Fatalf("Error writing diagram: %v", err)
+	}
+	if b.String() != expected {
+		t.Errorf("Expected:\n%v\ngot:\n%v", expected, b.String())
+	}
+}
+
+func TestFetch(t *testing.T) {
+	s := gons3test.NewServer()
+	defer s.Close()
+	g := s.Client()
+
+	p := gons3.ProjectCreator{}
+	p.SetName("TestFetch")
+	proj, err := gons3.CreateProject(g, p)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	nodes := []gons3.Node{}
+	for _, name := range []string{"PC1", "PC2"} {
+		n := gons3.NodeCreator{}
+		n.SetName(name)
+		n.SetNodeType("vpcs")
+		n.SetComputeID("local")
+		node, err := gons3.CreateNode(g, proj.ProjectID, n)
+		if err != nil {
+			t.Fatalf("Error creating node: %v", err)
+		}
+		nodes = append(nodes, node)
+	}
+	l := gons3.LinkCreator{}
+	l.AddNode(nodes[0].NodeID, 0, 0)
+	l.AddNode(nodes[1].NodeID, 0, 0)
+	if _, err := gons3.CreateLink(g, proj.ProjectID, l); err != nil {
+		t.Fatalf("Error creating link: %v", err)
+	}
+
+	dot, err := diagram.DOT(g, proj.ProjectID)
+	if err != nil {
+		t.Fatalf("Error rendering DOT: %v", err)
+	}
+	if !strings.Contains(dot, `graph "TestFetch"`) || !strings.Contains(dot, `taillabel="Ethernet0"`) {
+		t.Errorf("Unexpected DOT diagram:\n%v", dot)
+	}
+	mermaid, err := diagram.Mermaid(g, proj.ProjectID)
+	if err != nil {
+		t.Fatalf("Error rendering Mermaid: %v", err)
+	}
+	if !strings.Contains(mermaid, `n0 ---|"Ethernet0 - Ethernet0"| n1`) {
+		t.Errorf("Unexpected Mermaid diagram:\n%v", mermaid)
+	}
+}
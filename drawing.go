@@ -0,0 +1,159 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/drawing.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/drawing_handler.py
+
+package gons3
+
+import (
+	"net/url"
+)
+
+// Drawing models an instance of a GNS3 drawing.
+type Drawing struct {
+	DrawingID string `json:"drawing_id"`
+	ProjectID string `json:"project_id"`
+	SVG       string `json:"svg"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Z         int    `json:"z"`
+	Rotation  int    `json:"rotation"`
+	Locked    bool   `json:"locked"`
+}
+
+// CreateDrawing creates a GNS3 drawing in the specified project.
+func CreateDrawing(g GNS3Client, projectID string, d DrawingCreator) (Drawing, error) {
+	if projectID == "" {
+		return Drawing{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/drawings"
+	drawing := Drawing{}
+	if err := post(g, path, 201, d.values, &drawing); err != nil {
+		return Drawing{}, err
+	}
+	return drawing, nil
+}
+
+// UpdateDrawing updates a GNS3 drawing.
+func UpdateDrawing(g GNS3Client, projectID, drawingID string, d DrawingUpdater) (Drawing, error) {
+	if projectID == "" || drawingID == "" {
+		return Drawing{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/drawings/" + url.PathEscape(drawingID)
+	drawing := Drawing{}
+	if err := put(g, path, 201, d.values, &drawing); err != nil {
+		return Drawing{}, err
+	}
+	return drawing, nil
+}
+
+// DeleteDrawing deletes a GNS3 drawing.
+func DeleteDrawing(g GNS3Client, projectID, drawingID string) error {
+	if projectID == "" || drawingID == "" {
+		return ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/drawings/" + url.PathEscape(drawingID)
+	if err := delete(g, path, 204, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDrawing gets a GNS3 drawing.
+func GetDrawing(g GNS3Client, projectID, drawingID string) (Drawing, error) {
+	if projectID == "" || drawingID == "" {
+		return Drawing{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/drawings/" + url.PathEscape(drawingID)
+	drawing := Drawing{}
+	if err := get(g, path, 200, &drawing); err != nil {
+		return Drawing{}, err
+	}
+	return drawing, nil
+}
+
+// GetDrawings gets all the GNS3 drawings in the specified project.
+func GetDrawings(g GNS3Client, projectID string) ([]Drawing, error) {
+	if projectID == "" {
+		return []Drawing{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/drawings"
+	drawings := []Drawing{}
+	if err := get(g, path, 200, &drawings); err != nil {
+		return []Drawing{}, err
+	}
+	return drawings, nil
+}
+
+// DrawingCreator models a new GNS3 drawing.
+type DrawingCreator struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the drawing.
+func (d *DrawingCreator) SetProperty(name string, value interface{}) {
+	if d.values == nil {
+		d.values = map[string]interface{}{}
+	}
+	d.values[name] = value
+}
+
+// SetSVG sets the svg content for the new drawing.
+func (d *DrawingCreator) SetSVG(svg string) {
+	d.SetProperty("svg", svg)
+}
+
+// SetPosition sets the x, y and z position for the new drawing.
+func (d *DrawingCreator) SetPosition(x, y, z int) {
+	d.SetProperty("x", x)
+	d.SetProperty("y", y)
+	d.SetProperty("z", z)
+}
+
+// SetRotation sets the rotation for the new drawing.
+func (d *DrawingCreator) SetRotation(rotation int) {
+	d.SetProperty("rotation", rotation)
+}
+
+// SetLocked sets the locked option for the new drawing.
+func (d *DrawingCreator) SetLocked(locked bool) {
+	d.SetProperty("locked", locked)
+}
+
+// DrawingUpdater models an update to a GNS3 drawing.
+type DrawingUpdater struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the drawing.
+func (d *DrawingUpdater) SetProperty(name string, value interface{}) {
+	if d.values == nil {
+		d.values = map[string]interface{}{}
+	}
+	d.values[name] = value
+}
+
+// SetSVG sets the svg content for the drawing.
+func (d *DrawingUpdater) SetSVG(svg string) {
+	d.SetProperty("svg", svg)
+}
+
+// SetPosition sets the x, y and z position for the drawing.
+func (d *DrawingUpdater) SetPosition(x, y, z int) {
+	d.SetProperty("x", x)
+	d.SetProperty("y", y)
+	d.SetProperty("z", z)
+}
+
+// SetRotation sets the rotation for the drawing.
+func (d *DrawingUpdater) SetRotation(rotation int) {
+	d.SetProperty("rotation", rotation)
+}
+
+// SetLocked sets the locked option for the drawing.
+func (d *DrawingUpdater) SetLocked(locked bool) {
+	d.SetProperty("locked", locked)
+}
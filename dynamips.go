@@ -0,0 +1,53 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/node_handler.py
+
+package gons3
+
+import (
+	"net/url"
+)
+
+// GetNodeIdlePCProposals computes Idle-PC values for a Dynamips router. The
+// server starts the router if needed and the computation takes a while.
+func GetNodeIdlePCProposals(g GNS3Client, projectID, nodeID string) ([]string, error) {
+	if projectID == "" || nodeID == "" {
+		return []string{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/dynamips/idlepc_proposals"
+	proposals := []string{}
+	if err := get(g, path, 200, &proposals); err != nil {
+		return []string{}, err
+	}
+	return proposals, nil
+}
+
+// AutoIdlePC computes the best Idle-PC value for a Dynamips router.
+func AutoIdlePC(g GNS3Client, projectID, nodeID string) (string, error) {
+	if projectID == "" || nodeID == "" {
+		return "", ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/dynamips/auto_idlepc"
+	result := struct {
+		IdlePC string `json:"idlepc"`
+	}{}
+	if err := get(g, path, 200, &result); err != nil {
+		return "", err
+	}
+	return result.IdlePC, nil
+}
+
+// SetNodeIdlePC sets the Idle-PC value of a Dynamips router.
+func SetNodeIdlePC(g GNS3Client, projectID, nodeID, idlePC string) (Node, error) {
+	u := NodeUpdater{}
+	u.SetNodeProperty("idlepc", idlePC)
+	return UpdateNode(g, projectID, nodeID, u)
+}
+
+// SetTemplateIdlePC sets the Idle-PC value of a Dynamips template, which is
+// used by the routers created from it afterwards.
+func SetTemplateIdlePC(g GNS3Client, templateID, idlePC string) (Template, error) {
+	u := TemplateUpdater{}
+	u.SetProperty("idlepc", idlePC)
+	return UpdateTemplate(g, templateID, u)
+}
@@ -0,0 +1,69 @@
+package gons3
+
+import (
+	"regexp"
+	"strings"
+)
+
+// endpointCollections are the path segments whose following segment is an id.
+var endpointCollections = map[string]bool{
+	"appliances": true,
+	"computes":   true,
+	"drawings":   true,
+	"links":      true,
+	"nodes":      true,
+	"projects":   true,
+	"snapshots":  true,
+	"templates":  true,
+}
+
+// idSegment matches the path segments that are ids: UUIDs, such as project
+// ids, or numbers. Fixed routes such as "/v2/projects/load" are kept.
+var idSegment = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9]+)$`)
+
+// isIDSegment returns true if the segment follows a collection and is an id.
+func isIDSegment(prev, segment string) bool {
+	return endpointCollections[prev] && idSegment.MatchString(segment)
+}
+
+// EndpointTemplate converts a request path into its endpoint template by
+// replacing ids with "{id}" and file paths with "{path}".
+// For example "/v2/projects/1c4f.../nodes/8a2e.../start" becomes
+// "/v2/projects/{id}/nodes/{id}/start".
+func EndpointTemplate(path string) string {
+	if i := strings.IndexAny(path, "?#"); i != -1 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		switch prev := segments[i-1]; {
+		case prev == "files" && i >= 2 && segments[i-2] == "{id}":
+			return strings.Join(append(segments[:i], "{path}"), "/")
+		case isIDSegment(prev, segments[i]):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// endpointIDs returns the ids found in a request path keyed by their attribute
+// name, such as "project_id" and "node_id".
+func endpointIDs(path string) map[string]string {
+	if i := strings.IndexAny(path, "?#"); i != -1 {
+		path = path[:i]
+	}
+
+	ids := map[string]string{}
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		prev := segments[i-1]
+		if prev == "files" {
+			break
+		}
+		if isIDSegment(prev, segments[i]) {
+			ids[strings.TrimSuffix(prev, "s")+"_id"] = segments[i]
+		}
+	}
+	return ids
+}
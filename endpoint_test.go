@@ -0,0 +1,24 @@
+package gons3_test
+
+import (
+	"gons3"
+	"testing"
+)
+
+func TestEndpointTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/v2/projects":      "/v2/projects",
+		"/v2/projects/load": "/v2/projects/load",
+		"/v2/projects/6d3b9c44-4c1f-4f5e-9d5c-2b3d7e8f9a10":                                                                                    "/v2/projects/{id}",
+		"/v2/projects/6d3b9c44-4c1f-4f5e-9d5c-2b3d7e8f9a10/nodes/1f2e3d4c-5b6a-4798-8a9b-0c1d2e3f4a5b/start":                                   "/v2/projects/{id}/nodes/{id}/start",
+		"/v2/projects/6d3b9c44-4c1f-4f5e-9d5c-2b3d7e8f9a10/files/project-files/qemu/disk.qcow2":                                                "/v2/projects/{id}/files/{path}",
+		"/v2/projects/6d3b9c44-4c1f-4f5e-9d5c-2b3d7e8f9a10/links/9a8b7c6d-1e2f-4a3b-8c4d-5e6f7a8b9c0d/start_capture?data_link_type=DLT_EN10MB": "/v2/projects/{id}/links/{id}/start_capture",
+		"/v2/computes/local/images": "/v2/computes/local/images",
+		"/v2/computes/42":           "/v2/computes/{id}",
+	}
+	for path, expected := range tests {
+		if actual := gons3.EndpointTemplate(path); actual != expected {
+			t.Errorf("Expected template for %v: %v, got %v", path, expected, actual)
+		}
+	}
+}
@@ -26,14 +26,16 @@ func (e WrappedError) Unwrap() error {
 	return e.Next
 }
 
-// Is implements "errors.Is" for the current and wrapped errors.
-func (e WrappedError) Is(err error) bool {
-	return errors.Is(err, e.Current)
+// Is implements "errors.Is" for the current error.
+// The wrapped errors are checked by errors.Is through Unwrap.
+func (e WrappedError) Is(target error) bool {
+	return errors.Is(e.Current, target)
 }
 
-// As implements "errors.As" for the current and wrapped errors.
-func (e WrappedError) As(err error, target interface{}) bool {
-	return errors.As(err, e.Current)
+// As implements "errors.As" for the current error.
+// The wrapped errors are checked by errors.As through Unwrap.
+func (e WrappedError) As(target interface{}) bool {
+	return errors.As(e.Current, target)
 }
 
 // Wrap wraps the errors so the first wraps the second, and the second wraps the third, etc..
@@ -52,10 +54,42 @@ func Wrap(errs ...error) error {
 	return last
 }
 
-// ServerError represents a GNS3 server error and message
+// ErrNotFound is matched by errors.Is for server errors with status code 404.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is matched by errors.Is for server errors with status code 409.
+var ErrConflict = errors.New("conflict")
+
+// ErrValidation is matched by errors.Is for server errors with status code 400
+// that report a schema validation failure.
+var ErrValidation = errors.New("validation failed")
+
+// IsNotFound returns true if err is a server error with status code 404.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict returns true if err is a server error with status code 409.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidation returns true if err is a server error with status code 400 that
+// reports a schema validation failure.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// ServerError represents a GNS3 server error and message.
+// ServerError values are comparable.
 type ServerError struct {
-	msg  string
-	code int
+	code       int
+	msg        string
+	fullMsg    string
+	body       string
+	method     string
+	url        string
+	validation *ValidationError
 }
 
 // GetStatusCode gets the status code from the server
@@ -63,6 +97,37 @@ func (s ServerError) GetStatusCode() int {
 	return s.code
 }
 
+// GetMessage gets the server message without any schema details.
+func (s ServerError) GetMessage() string {
+	return s.msg
+}
+
+// GetFullMessage gets the complete server message.
+func (s ServerError) GetFullMessage() string {
+	return s.fullMsg
+}
+
+// GetBody gets the response body returned by the server.
+func (s ServerError) GetBody() []byte {
+	return []byte(s.body)
+}
+
+// GetMethod gets the method of the failed request.
+func (s ServerError) GetMethod() string {
+	return s.method
+}
+
+// GetURL gets the URL of the failed request.
+func (s ServerError) GetURL() string {
+	return s.url
+}
+
+// GetValidation gets the schema validation details, or nil if the server
+// did not report a schema validation error.
+func (s ServerError) GetValidation() *ValidationError {
+	return s.validation
+}
+
 // Error returns the error message for the ServerError
 func (s ServerError) Error() string {
 	if s.msg == "" {
@@ -71,36 +136,67 @@ func (s ServerError) Error() string {
 	return fmt.Sprintf("status code %v: %v", s.code, s.msg)
 }
 
-func newServerError(resp *http.Response) ServerError {
-	// Handle non-JSON error messages
-	contentType := resp.Header.Get("Content-type")
-	if !strings.Contains(contentType, "application/json") {
-		return ServerError{code: resp.StatusCode}
+// Is implements "errors.Is" for ErrNotFound, ErrConflict and ErrValidation.
+func (s ServerError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return s.code == http.StatusNotFound
+	case ErrConflict:
+		return s.code == http.StatusConflict
+	case ErrValidation:
+		return s.code == http.StatusBadRequest && s.validation != nil
+	}
+	return false
+}
+
+// As implements "errors.As" for *ServerError targets, so a pointer to the
+// error can be extracted as well as the value.
+func (s ServerError) As(target interface{}) bool {
+	if p, ok := target.(**ServerError); ok {
+		*p = &s
+		return true
+	}
+	return false
+}
+
+func newServerError(req *http.Request, resp *http.Response) ServerError {
+	serverErr := ServerError{code: resp.StatusCode}
+	if req != nil {
+		serverErr.method = req.Method
+		serverErr.url = req.URL.String()
 	}
 
-	// Unmarshal response to GNS3 error handler
-	// If this errors we'll just show the status code, something has gone wrong!
 	// Read body, ignoring error
+	// If this errors we'll just show the status code, something has gone wrong!
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return ServerError{code: resp.StatusCode}
+		return serverErr
 	}
+	serverErr.body = string(respBody)
 
-	// Unmarshal JSON, ignoring error
-	j := struct {
-		Message string `json:"message"`
-	}{}
-	if json.Unmarshal(respBody, &j) != nil {
-		return ServerError{code: resp.StatusCode}
+	serverErr.fullMsg = serverErrorMessage(resp.Header, respBody)
+	serverErr.msg = serverErr.fullMsg
+	serverErr.validation = parseValidationError(serverErr.fullMsg)
+	if serverErr.validation != nil {
+		serverErr.msg = serverErr.validation.Message
 	}
+	return serverErr
+}
 
-	// Ignore the schema message which is a rather large escaped JSON payload
-	if i := strings.Index(j.Message, " in schema"); i != -1 {
-		j.Message = j.Message[:i]
+// serverErrorMessage gets the message from a GNS3 error response body.
+func serverErrorMessage(header http.Header, body []byte) string {
+	// Handle non-JSON error messages
+	contentType := header.Get("Content-type")
+	if !strings.Contains(contentType, "application/json") {
+		return ""
 	}
 
-	return ServerError{
-		code: resp.StatusCode,
-		msg:  j.Message,
+	// Unmarshal JSON, ignoring error
+	j := struct {
+		Message string `json:"message"`
+	}{}
+	if json.Unmarshal(body, &j) != nil {
+		return ""
 	}
+	return j.Message
 }
@@ -0,0 +1,364 @@
+package gons3_test
+
+import (
+	"encoding/json"
+	"errors"
+	"gons3"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+var (
+	errA = errors.New("a")
+	errB = errors.New("b")
+	errC = errors.New("c")
+	errD = errors.New("d")
+)
+
+type stubClient struct {
+	resp *http.Response
+	err  error
+}
+
+func (s stubClient) GetSchemeAuthority() string {
+	return "http://gns3.test"
+}
+
+func (s stubClient) Do(req *http.Request) (*http.Response, error) {
+	if s.resp != nil {
+		s.resp.Request = req
+	}
+	return s.resp, s.err
+}
+
+func response(statusCode int, contentType, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+	if contentType != "" {
+		resp.Header.Set("Content-Type", contentType)
+	}
+	return resp
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		errs     []error
+		expected string
+	}{
+		{"single", []error{errA}, "a"},
+		{"pair", []error{errA, errB}, "a: b"},
+		{"triple", []error{errA, errB, errC}, "a: b: c"},
+		{"nested", []error{errA, gons3.Wrap(errB, errC), errD}, "a: b: c: d"},
+	}
+	for _, tt := range tests {
+		err := gons3.Wrap(tt.errs...)
+		if err.Error() != tt.expected {
+			t.Errorf("%v: Expected message: %v, got %v", tt.name, tt.expected, err.Error())
+		}
+	}
+
+	if err := gons3.Wrap(); err != nil {
+		t.Errorf("Expected nil when wrapping nothing, got %v", err)
+	}
+}
+
+func TestWrappedErrorIs(t *testing.T) {
+	serverErr := gons3.Wrap(errA, errB, errC)
+	nested := gons3.Wrap(errA, gons3.Wrap(errB, errC), errD)
+
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{"first", serverErr, errA, true},
+		{"middle", serverErr, errB, true},
+		{"last", serverErr, errC, true},
+		{"missing", serverErr, errD, false},
+		{"nested first", nested, errA, true},
+		{"nested inner", nested, errC, true},
+		{"nested last", nested, errD, true},
+		{"chain itself", serverErr, serverErr, true},
+		{"equal sub chain", serverErr, gons3.Wrap(errB, errC), true},
+		{"other chain", serverErr, gons3.Wrap(errA, errC), false},
+	}
+	for _, tt := range tests {
+		if actual := errors.Is(tt.err, tt.target); actual != tt.expected {
+			t.Errorf("%v: Expected errors.Is: %v, got %v", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestWrappedErrorUnwrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{"single", gons3.Wrap(errA), []string{"a"}},
+		{"pair", gons3.Wrap(errA, errB), []string{"a: b", "b"}},
+		{"triple", gons3.Wrap(errA, errB, errC), []string{"a: b: c", "b: c", "c"}},
+	}
+	for _, tt := range tests {
+		actual := []string{}
+		for err := tt.err; err != nil; err = errors.Unwrap(err) {
+			actual = append(actual, err.Error())
+		}
+		if strings.Join(actual, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("%v: Expected chain: %v, got %v", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestWrappedErrorAs(t *testing.T) {
+	urlErr := &url.Error{Op: "Get", URL: "http://gns3.test", Err: &net.OpError{Op: "dial", Err: errD}}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"current", gons3.Wrap(urlErr, errA), true},
+		{"middle", gons3.Wrap(errA, urlErr, errB), true},
+		{"last", gons3.Wrap(errA, errB, urlErr), true},
+		{"nested", gons3.Wrap(errA, gons3.Wrap(errB, urlErr)), true},
+		{"missing", gons3.Wrap(errA, errB), false},
+	}
+	for _, tt := range tests {
+		var target *url.Error
+		if actual := errors.As(tt.err, &target); actual != tt.expected {
+			t.Errorf("%v: Expected errors.As: %v, got %v", tt.name, tt.expected, actual)
+		}
+		if tt.expected && target != urlErr {
+			t.Errorf("%v: Expected target: %v, got %v", tt.name, urlErr, target)
+		}
+	}
+
+	var opErr *net.OpError
+	if !errors.As(gons3.Wrap(errA, urlErr), &opErr) {
+		t.Errorf("Expected errors.As to find *net.OpError inside *url.Error")
+	}
+	var wrapped gons3.WrappedError
+	if !errors.As(gons3.Wrap(errA, errB), &wrapped) || wrapped.Current != errA {
+		t.Errorf("Expected errors.As to find WrappedError, got %v", wrapped)
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	urlErr := &url.Error{Op: "Get", URL: "http://gns3.test", Err: errA}
+	schemaMsg := `{"message": "Invalid JSON: 'name' is a required property in schema: {\"type\": \"object\"}", "status": 400}`
+	verboseMsg := `{"message": "'abc' is not of type 'integer'\n\nFailed validating 'type' in schema['properties']['zoom']:\n    {\"type\": \"integer\"}\n\nOn instance['zoom']:\n    'abc'", "status": 400}`
+
+	tests := []struct {
+		name    string
+		client  stubClient
+		is      []error
+		isNot   []error
+		as      func(err error) bool
+		message string
+	}{
+		{
+			name:   "request failed",
+			client: stubClient{err: urlErr},
+			is:     []error{gons3.ErrRequestFailed, errA},
+			isNot:  []error{gons3.ErrUnexpectedStatusCode},
+			as: func(err error) bool {
+				var target *url.Error
+				return errors.As(err, &target) && target == urlErr
+			},
+		},
+		{
+			name:    "not found",
+			client:  stubClient{resp: response(404, "application/json", `{"message": "Project ID x doesn't exist", "status": 404}`)},
+			is:      []error{gons3.ErrUnexpectedStatusCode, gons3.ErrNotFound},
+			isNot:   []error{gons3.ErrConflict, gons3.ErrValidation, gons3.ErrRequestFailed},
+			message: "unexpected status code: status code 404: Project ID x doesn't exist",
+			as: func(err error) bool {
+				var target gons3.ServerError
+				return errors.As(err, &target) && target.GetStatusCode() == 404 && target.GetMethod() == "GET"
+			},
+		},
+		{
+			name:    "conflict without json",
+			client:  stubClient{resp: response(409, "text/plain", "conflict")},
+			is:      []error{gons3.ErrUnexpectedStatusCode, gons3.ErrConflict},
+			isNot:   []error{gons3.ErrNotFound},
+			message: "unexpected status code: status code 409",
+			as: func(err error) bool {
+				var target gons3.ServerError
+				return errors.As(err, &target) && string(target.GetBody()) == "conflict"
+			},
+		},
+		{
+			name:    "schema validation",
+			client:  stubClient{resp: response(400, "application/json", schemaMsg)},
+			is:      []error{gons3.ErrUnexpectedStatusCode, gons3.ErrValidation},
+			message: "unexpected status code: status code 400: Invalid JSON: 'name' is a required property",
+			as: func(err error) bool {
+				var target gons3.ServerError
+				if !errors.As(err, &target) || target.GetValidation() == nil {
+					return false
+				}
+				v := target.GetValidation()
+				return v.Validator == "required" && v.JSONPath == "$.name" && string(v.Schema) == `{"type": "object"}`
+			},
+		},
+		{
+			name:    "bad request without schema",
+			client:  stubClient{resp: response(400, "application/json", `{"message": "Cannot open a project without a name", "status": 400}`)},
+			is:      []error{gons3.ErrUnexpectedStatusCode},
+			isNot:   []error{gons3.ErrValidation},
+			message: "unexpected status code: status code 400: Cannot open a project without a name",
+			as: func(err error) bool {
+				var target gons3.ServerError
+				return errors.As(err, &target) && target.GetStatusCode() == 400 && target.GetValidation() == nil
+			},
+		},
+		{
+			name:    "verbose schema validation",
+			client:  stubClient{resp: response(400, "application/json", verboseMsg)},
+			is:      []error{gons3.ErrValidation},
+			message: "unexpected status code: status code 400: 'abc' is not of type 'integer'",
+			as: func(err error) bool {
+				var target gons3.ServerError
+				if !errors.As(err, &target) || target.GetValidation() == nil {
+					return false
+				}
+				v := target.GetValidation()
+				return v.Validator == "type" && v.JSONPath == "$.zoom" && v.SchemaPath == "$.properties.zoom"
+			},
+		},
+		{
+			name:   "response not json",
+			client: stubClient{resp: response(200, "text/html", "<html></html>")},
+			is:     []error{gons3.ErrResponseNotJSON},
+			isNot:  []error{gons3.ErrFailedToUnmarshalResponse},
+		},
+		{
+			name:   "malformed json",
+			client: stubClient{resp: response(200, "application/json", `{"name": `)},
+			is:     []error{gons3.ErrFailedToUnmarshalResponse},
+			as: func(err error) bool {
+				var target *json.SyntaxError
+				return errors.As(err, &target)
+			},
+		},
+		{
+			name:   "mismatched json",
+			client: stubClient{resp: response(200, "application/json", `{"name": 5}`)},
+			is:     []error{gons3.ErrFailedToUnmarshalResponse},
+			as: func(err error) bool {
+				var target *json.UnmarshalTypeError
+				return errors.As(err, &target) && target.Field == "name"
+			},
+		},
+	}
+	for _, tt := range tests {
+		_, err := gons3.GetProject(tt.client, "x")
+		if err == nil {
+			t.Errorf("%v: Expected an error", tt.name)
+			continue
+		}
+		for _, target := range tt.is {
+			if !errors.Is(err, target) {
+				t.Errorf("%v: Expected errors.Is(%v): %v, got %v", tt.name, target, true, false)
+			}
+		}
+		for _, target := range tt.isNot {
+			if errors.Is(err, target) {
+				t.Errorf("%v: Expected errors.Is(%v): %v, got %v", tt.name, target, false, true)
+			}
+		}
+		if tt.as != nil && !tt.as(err) {
+			t.Errorf("%v: Expected errors.As to match %v", tt.name, err)
+		}
+		if tt.message != "" && err.Error() != tt.message {
+			t.Errorf("%v: Expected message: %v, got %v", tt.name, tt.message, err.Error())
+		}
+	}
+}
+
+func TestServerErrorHelpers(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		notFound   bool
+		conflict   bool
+		validation bool
+	}{
+		{400, false, false, false},
+		{404, true, false, false},
+		{409, false, true, false},
+		{500, false, false, false},
+	}
+	for _, tt := range tests {
+		_, err := gons3.GetProject(stubClient{resp: response(tt.statusCode, "", "")}, "x")
+		if gons3.IsNotFound(err) != tt.notFound {
+			t.Errorf("%v: Expected IsNotFound: %v, got %v", tt.statusCode, tt.notFound, !tt.notFound)
+		}
+		if gons3.IsConflict(err) != tt.conflict {
+			t.Errorf("%v: Expected IsConflict: %v, got %v", tt.statusCode, tt.conflict, !tt.conflict)
+		}
+		if gons3.IsValidation(err) != tt.validation {
+			t.Errorf("%v: Expected IsValidation: %v, got %v", tt.statusCode, tt.validation, !tt.validation)
+		}
+	}
+}
+
+func TestServerErrorComparable(t *testing.T) {
+	var errs []gons3.ServerError
+	for i := 0; i < 2; i++ {
+		_, err := gons3.GetProject(stubClient{resp: response(409, "text/plain", "conflict")}, "x")
+		var target gons3.ServerError
+		if !errors.As(err, &target) {
+			t.Fatalf("Expected a ServerError, got %v", err)
+		}
+		errs = append(errs, target)
+	}
+	if errs[0] != errs[1] {
+		t.Errorf("Expected equal server errors: %v and %v", errs[0], errs[1])
+	}
+	seen := map[gons3.ServerError]bool{errs[0]: true}
+	if !seen[errs[1]] {
+		t.Errorf("Expected server errors to be usable as map keys")
+	}
+}
+
+func TestServerErrorAsPointer(t *testing.T) {
+	_, err := gons3.GetProject(stubClient{resp: response(404, "application/json", `{"message": "Project ID x doesn't exist", "status": 404}`)}, "x")
+	var value gons3.ServerError
+	if !errors.As(err, &value) {
+		t.Fatalf("Expected a ServerError, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"value", value, true},
+		{"request", err, true},
+		{"wrapped", gons3.Wrap(errA, err), true},
+		{"nested", gons3.Wrap(errA, gons3.Wrap(errB, value), errC), true},
+		{"current", gons3.Wrap(value, errA), true},
+		{"missing", gons3.Wrap(errA, errB), false},
+	}
+	for _, tt := range tests {
+		var target *gons3.ServerError
+		if actual := errors.As(tt.err, &target); actual != tt.expected {
+			t.Errorf("%v: Expected errors.As: %v, got %v", tt.name, tt.expected, actual)
+			continue
+		}
+		if tt.expected && (target == nil || *target != value) {
+			t.Errorf("%v: Expected target: %v, got %v", tt.name, value, target)
+		}
+	}
+}
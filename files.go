@@ -0,0 +1,63 @@
+package gons3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidFilepath means that the filepath is absolute or leaves the project
+// or node directory. Errors are returned as *InvalidFilepathError.
+var ErrInvalidFilepath = errors.New("invalid filepath")
+
+// InvalidFilepathError is returned for a file path that is rejected before
+// any request is sent.
+type InvalidFilepathError struct {
+	Filepath string
+	Reason   string
+}
+
+func (e *InvalidFilepathError) Error() string {
+	return fmt.Sprintf("invalid filepath %q: %s", e.Filepath, e.Reason)
+}
+
+// Is returns true for ErrInvalidFilepath.
+func (e *InvalidFilepathError) Is(target error) bool {
+	return target == ErrInvalidFilepath
+}
+
+// escapeFilepath checks a slash separated path relative to a project or
+// node directory and escapes each of its segments for the URL. Empty and
+// "." segments are dropped.
+func escapeFilepath(filepath string) (string, error) {
+	if filepath == "" {
+		return "", ErrEmptyFilepath
+	}
+	invalid := func(reason string) (string, error) {
+		return "", &InvalidFilepathError{Filepath: filepath, Reason: reason}
+	}
+	if strings.HasPrefix(filepath, "/") {
+		return invalid("absolute paths are not allowed")
+	}
+	if strings.ContainsAny(filepath, "\\\x00") {
+		return invalid("backslashes and NUL characters are not allowed")
+	}
+
+	segments := []string{}
+	for i, s := range strings.Split(filepath, "/") {
+		switch {
+		case s == "" || s == ".":
+			continue
+		case s == "..":
+			return invalid("parent directory segments are not allowed")
+		case i == 0 && len(s) == 2 && s[1] == ':':
+			return invalid("absolute paths are not allowed")
+		}
+		segments = append(segments, url.PathEscape(s))
+	}
+	if len(segments) == 0 {
+		return "", ErrEmptyFilepath
+	}
+	return strings.Join(segments, "/"), nil
+}
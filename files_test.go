@@ -0,0 +1,99 @@
+package gons3_test
+
+import (
+	"errors"
+	"gons3"
+	"net/http"
+	"testing"
+)
+
+// urlClient records the escaped path of the last request and answers it
+// successfully, with 201 for the POST requests that create files.
+type urlClient struct {
+	path string
+}
+
+func (u *urlClient) GetSchemeAuthority() string {
+	return "http://gns3.test"
+}
+
+func (u *urlClient) Do(req *http.Request) (*http.Response, error) {
+	u.path = req.URL.EscapedPath()
+	if req.Method == "POST" {
+		return response(201, "", ""), nil
+	}
+	return response(200, "application/octet-stream", ""), nil
+}
+
+func TestFilepathEscaping(t *testing.T) {
+	tests := []struct {
+		name     string
+		filepath string
+		expected string
+	}{
+		{"Plain", "configs/startup.cfg", "configs/startup.cfg"},
+		{"Space", "my config.txt", "my%20config.txt"},
+		{"Fragment", "notes#1.txt", "notes%231.txt"},
+		{"Query", "what?.txt", "what%3F.txt"},
+		{"Percent", "100%.txt", "100%25.txt"},
+		{"Unicode", "конфиг/résumé.txt", "%D0%BA%D0%BE%D0%BD%D1%84%D0%B8%D0%B3/r%C3%A9sum%C3%A9.txt"},
+		{"DotSegments", "./a//b/./c", "a/b/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &urlClient{}
+			if _, err := gons3.ReadProjectFile(g, "p", tt.filepath); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if expected := "/v2/projects/p/files/" + tt.expected; g.path != expected {
+				t.Errorf("Expected path: %v, got %v", expected, g.path)
+			}
+			if err := gons3.WriteNodeFile(g, "p", "n", tt.filepath, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if expected := "/v2/projects/p/nodes/n/files/" + tt.expected; g.path != expected {
+				t.Errorf("Expected path: %v, got %v", expected, g.path)
+			}
+		})
+	}
+}
+
+func TestInvalidFilepath(t *testing.T) {
+	tests := []struct {
+		name     string
+		filepath string
+		err      error
+	}{
+		{"Empty", "", gons3.ErrEmptyFilepath},
+		{"OnlyDots", "./.", gons3.ErrEmptyFilepath},
+		{"Absolute", "/etc/passwd", gons3.ErrInvalidFilepath},
+		{"Parent", "../other/project.gns3", gons3.ErrInvalidFilepath},
+		{"InnerParent", "configs/../../x", gons3.ErrInvalidFilepath},
+		{"Backslash", "..\\x", gons3.ErrInvalidFilepath},
+		{"Drive", "C:/Windows", gons3.ErrInvalidFilepath},
+		{"NUL", "a\x00b", gons3.ErrInvalidFilepath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &urlClient{}
+			_, err := gons3.ReadProjectFile(g, "p", tt.filepath)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected error: %v, got %v", tt.err, err)
+			}
+			if g.path != "" {
+				t.Errorf("Expected no request, got %v", g.path)
+			}
+			var pathErr *gons3.InvalidFilepathError
+			if errors.As(err, &pathErr) != (tt.err == gons3.ErrInvalidFilepath) {
+				t.Errorf("Unexpected error type: %T", err)
+			}
+		})
+	}
+
+	_, err := gons3.ReadProjectFile(&urlClient{}, "p", "say \"hi\"\x00")
+	if expected := `invalid filepath "say \"hi\"\x00": backslashes and NUL characters are not allowed`; err == nil || err.Error() != expected {
+		t.Errorf("Expected message: %v, got %v", expected, err)
+	}
+}
@@ -5,17 +5,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
 )
 
-// GNS3HTTPClient represents a default GNS3 client and server.
+// GNS3HTTPClient represents a default GNS3 client and server. Requests are
+// sent with HTTP basic authentication when Username is set.
 type GNS3HTTPClient struct {
 	Client   *http.Client
 	Scheme   string
 	Hostname string
 	Port     int
+	Username string
+	Password string
 }
 
 // GetSchemeAuthority gets the scheme and authority of the GNS3 server.
@@ -34,6 +38,9 @@ func (g GNS3HTTPClient) GetSchemeAuthority() string {
 
 // Do sends the HTTP request with the default or explicit *http.Client.
 func (g GNS3HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if g.Username != "" {
+		req.SetBasicAuth(g.Username, g.Password)
+	}
 	if g.Client == nil {
 		return http.DefaultClient.Do(req)
 	}
@@ -77,6 +84,9 @@ var ErrUnexpectedStatusCode = errors.New("unexpected status code")
 // ErrFailedToReadResult is returned when response result could not be read into a buffer.
 var ErrFailedToReadResult = errors.New("failed to read response body")
 
+// ErrFailedToWriteResult is returned when a streamed response could not be written to the caller's writer.
+var ErrFailedToWriteResult = errors.New("failed to write response body")
+
 // ErrResponseNotJSON is returned when response was expected to be JSON, but was not.
 var ErrResponseNotJSON = errors.New("response was not json as expected")
 
@@ -84,16 +94,22 @@ var ErrResponseNotJSON = errors.New("response was not json as expected")
 var ErrFailedToUnmarshalResponse = errors.New("failed to unmarshal response")
 
 func req(g GNS3Client, method, url string, expectedStatus int, body, result interface{}) error {
-	var bodyReader *bytes.Reader
+	var bodyReader io.Reader
 	var contentType string
+	var contentLength int64
 
-	// Handle empty body, bytes body, or Marshal body to JSON
+	// Handle empty body, bytes body, streamed body, or Marshal body to JSON
 	switch b := body.(type) {
 	case nil:
 		bodyReader = bytes.NewReader([]byte{})
 	case *[]byte:
 		bodyReader = bytes.NewReader(*b)
 		contentType = "application/octet-stream"
+	case io.Reader:
+		// Do not let the transport close the caller's reader
+		bodyReader = ioutil.NopCloser(b)
+		contentLength = readerSize(b)
+		contentType = "application/octet-stream"
 	default:
 		reqBody, err := json.Marshal(body)
 		if err != nil {
@@ -111,6 +127,9 @@ func req(g GNS3Client, method, url string, expectedStatus int, body, result inte
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
+	if contentLength > 0 {
+		req.ContentLength = contentLength
+	}
 	defer req.Body.Close()
 
 	// Send request
@@ -122,7 +141,22 @@ func req(g GNS3Client, method, url string, expectedStatus int, body, result inte
 
 	// Check status code and return the error if possible
 	if resp.StatusCode != expectedStatus {
-		return Wrap(ErrUnexpectedStatusCode, newServerError(resp))
+		return Wrap(ErrUnexpectedStatusCode, newServerError(req, resp))
+	}
+
+	// Stream body
+	if w, ok := result.(io.Writer); ok {
+		if s, ok := w.(interface{ setSize(int64) }); ok {
+			s.setSize(resp.ContentLength)
+		}
+		ew := &errWriter{w: w}
+		if _, err := io.Copy(ew, resp.Body); err != nil {
+			if ew.err != nil {
+				return Wrap(ErrFailedToWriteResult, err)
+			}
+			return Wrap(ErrFailedToReadResult, err)
+		}
+		return nil
 	}
 
 	// Read body
@@ -140,10 +174,28 @@ func req(g GNS3Client, method, url string, expectedStatus int, body, result inte
 		if !strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
 			return ErrResponseNotJSON
 		}
-		if json.Unmarshal(respBody, &result); err != nil {
+		if err := json.Unmarshal(respBody, result); err != nil {
 			return Wrap(ErrFailedToUnmarshalResponse, err)
 		}
 	}
 
 	return nil
 }
+
+// errWriter remembers the error of the wrapped writer, so failures to write
+// a streamed response are told apart from failures to read it.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(b []byte) (int, error) {
+	n, err := e.w.Write(b)
+	if err == nil && n < len(b) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		e.err = err
+	}
+	return n, err
+}
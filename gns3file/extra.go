@@ -0,0 +1,140 @@
+package gns3file
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalJSON decodes the file, keeping unknown fields in Extra.
+func (f *File) UnmarshalJSON(data []byte) error {
+	type file File
+	return unmarshalExtra(data, (*file)(f), &f.Extra)
+}
+
+// MarshalJSON encodes the file with the fields of Extra.
+func (f File) MarshalJSON() ([]byte, error) {
+	type file File
+	return marshalExtra(file(f), f.Extra)
+}
+
+// UnmarshalJSON decodes the topology, keeping unknown fields in Extra.
+func (t *Topology) UnmarshalJSON(data []byte) error {
+	type topology Topology
+	return unmarshalExtra(data, (*topology)(t), &t.Extra)
+}
+
+// MarshalJSON encodes the topology with the fields of Extra.
+func (t Topology) MarshalJSON() ([]byte, error) {
+	type topology Topology
+	return marshalExtra(topology(t), t.Extra)
+}
+
+// UnmarshalJSON decodes the compute, keeping unknown fields in Extra.
+func (c *Compute) UnmarshalJSON(data []byte) error {
+	type compute Compute
+	return unmarshalExtra(data, (*compute)(c), &c.Extra)
+}
+
+// MarshalJSON encodes the compute with the fields of Extra.
+func (c Compute) MarshalJSON() ([]byte, error) {
+	type compute Compute
+	return marshalExtra(compute(c), c.Extra)
+}
+
+// UnmarshalJSON decodes the drawing, keeping unknown fields in Extra.
+func (d *Drawing) UnmarshalJSON(data []byte) error {
+	type drawing Drawing
+	return unmarshalExtra(data, (*drawing)(d), &d.Extra)
+}
+
+// MarshalJSON encodes the drawing with the fields of Extra.
+func (d Drawing) MarshalJSON() ([]byte, error) {
+	type drawing Drawing
+	return marshalExtra(drawing(d), d.Extra)
+}
+
+// UnmarshalJSON decodes the link endpoint, keeping unknown fields in Extra.
+func (n *LinkNode) UnmarshalJSON(data []byte) error {
+	type linkNode LinkNode
+	return unmarshalExtra(data, (*linkNode)(n), &n.Extra)
+}
+
+// MarshalJSON encodes the link endpoint with the fields of Extra.
+func (n LinkNode) MarshalJSON() ([]byte, error) {
+	type linkNode LinkNode
+	return marshalExtra(linkNode(n), n.Extra)
+}
+
+// UnmarshalJSON decodes the link, keeping unknown fields in Extra.
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type link Link
+	return unmarshalExtra(data, (*link)(l), &l.Extra)
+}
+
+// MarshalJSON encodes the link with the fields of Extra.
+func (l Link) MarshalJSON() ([]byte, error) {
+	type link Link
+	return marshalExtra(link(l), l.Extra)
+}
+
+// UnmarshalJSON decodes the node, keeping unknown fields in Extra.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	type node Node
+	return unmarshalExtra(data, (*node)(n), &n.Extra)
+}
+
+// MarshalJSON encodes the node with the fields of Extra.
+func (n Node) MarshalJSON() ([]byte, error) {
+	type node Node
+	return marshalExtra(node(n), n.Extra)
+}
+
+// unmarshalExtra decodes data into v and the fields v does not model into extra.
+func unmarshalExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name := range jsonFields(reflect.TypeOf(v).Elem()) {
+		delete(fields, name)
+	}
+	*extra = nil
+	if len(fields) > 0 {
+		*extra = fields
+	}
+	return nil
+}
+
+// marshalExtra encodes v with the fields of extra that v does not model.
+func marshalExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// jsonFields returns the JSON names of the fields of a struct type.
+func jsonFields(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			names[tag] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,200 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/controller/topology.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/topology.py
+
+// Package gns3file reads and writes .gns3 project files without a GNS3 server.
+//
+// Files are decoded into typed structs. Fields that are not modeled are kept
+// in Extra and written back, so a file can be read, changed and written
+// without losing data. Files are written the way GNS3 writes them, with
+// sorted keys and an indent of four spaces.
+package gns3file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gons3"
+	"io/ioutil"
+)
+
+// CurrentRevision is the topology revision written by GNS3 2.2.
+const CurrentRevision = 9
+
+// ErrNewerRevision is returned when migrating or writing files written by a
+// newer version of GNS3, which cannot be safely rewritten.
+var ErrNewerRevision = errors.New("gns3file: file revision is newer than supported")
+
+// File models a .gns3 project file.
+type File struct {
+	Name                string                     `json:"name"`
+	ProjectID           string                     `json:"project_id"`
+	Revision            int                        `json:"revision"`
+	Type                string                     `json:"type"`
+	Version             string                     `json:"version"`
+	AutoClose           bool                       `json:"auto_close"`
+	AutoOpen            bool                       `json:"auto_open"`
+	AutoStart           bool                       `json:"auto_start"`
+	SceneHeight         int                        `json:"scene_height"`
+	SceneWidth          int                        `json:"scene_width"`
+	Zoom                int                        `json:"zoom"`
+	ShowLayers          bool                       `json:"show_layers"`
+	SnapToGrid          bool                       `json:"snap_to_grid"`
+	ShowGrid            bool                       `json:"show_grid"`
+	GridSize            int                        `json:"grid_size"`
+	DrawingGridSize     int                        `json:"drawing_grid_size"`
+	ShowInterfaceLabels bool                       `json:"show_interface_labels"`
+	Supplier            *gons3.ProjectSupplier     `json:"supplier"`
+	Variables           []gons3.ProjectVariables   `json:"variables"`
+	Topology            Topology                   `json:"topology"`
+	Extra               map[string]json.RawMessage `json:"-"`
+}
+
+// Topology models the resources of a .gns3 project file.
+type Topology struct {
+	Computes []Compute                  `json:"computes"`
+	Drawings []Drawing                  `json:"drawings"`
+	Links    []Link                     `json:"links"`
+	Nodes    []Node                     `json:"nodes"`
+	Extra    map[string]json.RawMessage `json:"-"`
+}
+
+// Compute models a compute of a .gns3 project file.
+type Compute struct {
+	ComputeID string                     `json:"compute_id"`
+	Name      string                     `json:"name"`
+	Host      string                     `json:"host"`
+	Port      int                        `json:"port"`
+	Protocol  string                     `json:"protocol"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+// Drawing models a drawing of a .gns3 project file.
+type Drawing struct {
+	DrawingID string                     `json:"drawing_id"`
+	SVG       string                     `json:"svg"`
+	X         int                        `json:"x"`
+	Y         int                        `json:"y"`
+	Z         int                        `json:"z"`
+	Rotation  int                        `json:"rotation"`
+	Locked    bool                       `json:"locked"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+// LinkNode models an endpoint of a link of a .gns3 project file.
+type LinkNode struct {
+	NodeID        string                     `json:"node_id"`
+	AdapterNumber int                        `json:"adapter_number"`
+	PortNumber    int                        `json:"port_number"`
+	Label         *gons3.NodeLabel           `json:"label,omitempty"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+// Link models a link of a .gns3 project file.
+type Link struct {
+	LinkID    string                     `json:"link_id"`
+	Nodes     []LinkNode                 `json:"nodes"`
+	Suspend   bool                       `json:"suspend"`
+	Filters   map[string]interface{}     `json:"filters"`
+	LinkStyle map[string]interface{}     `json:"link_style"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+// Node models a node of a .gns3 project file.
+type Node struct {
+	NodeID           string                     `json:"node_id"`
+	Name             string                     `json:"name"`
+	NodeType         string                     `json:"node_type"`
+	ComputeID        string                     `json:"compute_id"`
+	TemplateID       *string                    `json:"template_id"`
+	Console          *int                       `json:"console"`
+	ConsoleType      string                     `json:"console_type"`
+	ConsoleAutoStart bool                       `json:"console_auto_start"`
+	Properties       map[string]interface{}     `json:"properties"`
+	Label            *gons3.NodeLabel           `json:"label"`
+	Symbol           string                     `json:"symbol"`
+	Width            int                        `json:"width"`
+	Height           int                        `json:"height"`
+	X                int                        `json:"x"`
+	Y                int                        `json:"y"`
+	Z                int                        `json:"z"`
+	Locked           bool                       `json:"locked"`
+	PortNameFormat   string                     `json:"port_name_format"`
+	PortSegmentSize  int                        `json:"port_segment_size"`
+	FirstPortName    *string                    `json:"first_port_name"`
+	CustomAdapters   []map[string]interface{}   `json:"custom_adapters"`
+	Extra            map[string]json.RawMessage `json:"-"`
+}
+
+// Parse decodes a .gns3 project file. Older files should be upgraded with
+// Migrate before they are written. Files with a newer revision than
+// CurrentRevision can be read and linted, with their unknown fields kept in
+// Extra, but Lint warns about them and they cannot be migrated or written.
+func Parse(data []byte) (*File, error) {
+	f := &File{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("gns3file: %v", err)
+	}
+	return f, nil
+}
+
+// ReadFile reads and decodes a .gns3 project file.
+func ReadFile(filename string) (*File, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Marshal validates and encodes the file the way GNS3 writes it. Files with a
+// newer revision than CurrentRevision return ErrNewerRevision.
+func (f *File) Marshal() ([]byte, error) {
+	if f.Revision > CurrentRevision {
+		return nil, ErrNewerRevision
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into maps to sort the keys of every object.
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "    ")
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// WriteFile validates and writes the file.
+func (f *File) WriteFile(filename string) error {
+	data, err := f.Marshal()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// Node returns the node with the specified name.
+func (f *File) Node(name string) (*Node, bool) {
+	for i := range f.Topology.Nodes {
+		if f.Topology.Nodes[i].Name == name {
+			return &f.Topology.Nodes[i], true
+		}
+	}
+	return nil, false
+}
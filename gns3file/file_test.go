@@ -0,0 +1,137 @@
+package gns3file_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"gons3/gns3file"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/lab.gns3")
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+	f, err := gns3file.Parse(data)
+	if err != nil {
+		t.Fatalf("Error parsing file: %v", err)
+	}
+	if len(f.Topology.Nodes) != 3 || len(f.Topology.Links) != 1 || len(f.Topology.Drawings) != 1 {
+		t.Errorf("Unexpected topology: %+v", f.Topology)
+	}
+	r1, ok := f.Node("R1")
+	if !ok || r1.Properties["ram"] != float64(512) || string(r1.Extra["aux"]) != "5002" {
+		t.Errorf("Unexpected node R1: %+v", r1)
+	}
+
+	out, err := f.Marshal()
+	if err != nil {
+		t.Fatalf("Error marshaling file: %v", err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Errorf("Expected the file to be written unchanged, got:\n%s", out)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	f, err := gns3file.ReadFile("testdata/lab-2.1.gns3")
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+	if !f.NeedsMigration() {
+		t.Errorf("Expected revision %v to need migration", f.Revision)
+	}
+	if _, err := f.Marshal(); err == nil || !strings.Contains(err.Error(), "must be migrated") {
+		t.Errorf("Expected a migration error, got %v", err)
+	}
+	if err := f.Migrate(); err != nil {
+		t.Fatalf("Error migrating file: %v", err)
+	}
+	r1, _ := f.Node("R1")
+	if _, ok := r1.Properties["acpi_shutdown"]; ok || r1.Properties["on_close"] != "shutdown_signal" {
+		t.Errorf("Expected acpi_shutdown to be replaced by on_close, got %v", r1.Properties)
+	}
+	if f.Revision != gns3file.CurrentRevision {
+		t.Errorf("Expected revision: %v, got %v", gns3file.CurrentRevision, f.Revision)
+	}
+
+	f.Revision = 5
+	if err := f.Migrate(); err != gns3file.ErrUnsupportedRevision {
+		t.Errorf("Expected error: %v, got %v", gns3file.ErrUnsupportedRevision, err)
+	}
+
+	newer, err := gns3file.Parse([]byte(`{"name": "lab", "project_id": "e7f1ef0c-4c34-4e13-9b3a-77e5e2d0a0c1", "revision": 10, "type": "topology", "future": {"a": 1}}`))
+	if err != nil {
+		t.Fatalf("Error parsing newer file: %v", err)
+	}
+	if string(newer.Extra["future"]) != `{"a": 1}` {
+		t.Errorf("Expected unknown field in Extra, got %v", newer.Extra)
+	}
+	if problems := newer.Lint(); len(problems) != 1 || problems[0].Path != "revision" {
+		t.Errorf("Expected a revision warning, got %v", problems)
+	}
+	if err := newer.Migrate(); err != gns3file.ErrNewerRevision {
+		t.Errorf("Expected error: %v, got %v", gns3file.ErrNewerRevision, err)
+	}
+	if _, err := newer.Marshal(); err != gns3file.ErrNewerRevision {
+		t.Errorf("Expected error: %v, got %v", gns3file.ErrNewerRevision, err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	f, err := gns3file.ReadFile("testdata/lab.gns3")
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+	if problems := f.Lint(); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %v", problems)
+	}
+
+	f.Topology.Nodes[1].Name = "PC1"
+	f.Topology.Nodes[2].ComputeID = "remote"
+	link := f.Topology.Links[0]
+	link.LinkID = "second"
+	link.Nodes = append([]gns3file.LinkNode{}, link.Nodes[0], gns3file.LinkNode{NodeID: "missing"})
+	f.Topology.Links = append(f.Topology.Links, link)
+
+	expected := []string{
+		`topology.nodes[1]: duplicate node name "PC1"`,
+		`topology.nodes[2]: unknown compute "remote"`,
+		`topology.links[1].nodes[0]: port 0/0 of node "PC1" is already used by link 7a6b5c4d-3e2f-4a1b-9c8d-7e6f5a4b3c2d`,
+		`topology.links[1].nodes[1]: unknown node missing`,
+	}
+	problems := f.Lint()
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected problems: %v, got %v", expected, problems)
+	}
+	for i, p := range problems {
+		if p.String() != expected[i] {
+			t.Errorf("Expected problem: %v, got %v", expected[i], p)
+		}
+	}
+
+	err = f.Validate()
+	if lint, ok := err.(*gns3file.LintError); !ok || len(lint.Problems) != len(expected) {
+		t.Errorf("Expected a LintError, got %v", err)
+	}
+}
+
+func TestNewFile(t *testing.T) {
+	f := &gns3file.File{Name: "new", ProjectID: "b1d5c3a2-6f4e-4d7c-8b9a-0e1f2a3b4c5d", Revision: gns3file.CurrentRevision, Type: "topology"}
+	f.Topology.Nodes = append(f.Topology.Nodes, gns3file.Node{NodeID: "n1", Name: "PC1", NodeType: "vpcs", ComputeID: "local"})
+	data, err := f.Marshal()
+	if err != nil {
+		t.Fatalf("Error marshaling file: %v", err)
+	}
+
+	v := map[string]interface{}{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Error unmarshaling file: %v", err)
+	}
+	node := v["topology"].(map[string]interface{})["nodes"].([]interface{})[0].(map[string]interface{})
+	if _, ok := node["template_id"]; !ok || node["console"] != nil {
+		t.Errorf("Expected template_id and a null console, got %v", node)
+	}
+}
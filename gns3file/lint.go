@@ -0,0 +1,126 @@
+package gns3file
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Problem is an inconsistency found in a file.
+type Problem struct {
+	Path    string // JSON path of the value, such as "topology.nodes[2]".
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Path + ": " + p.Message
+}
+
+// LintError is returned by Validate with the problems found in a file.
+type LintError struct {
+	Problems []Problem
+}
+
+func (e *LintError) Error() string {
+	messages := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		messages[i] = p.String()
+	}
+	return "gns3file: " + strings.Join(messages, "; ")
+}
+
+// Validate returns a *LintError if Lint finds problems.
+func (f *File) Validate() error {
+	if problems := f.Lint(); len(problems) > 0 {
+		return &LintError{Problems: problems}
+	}
+	return nil
+}
+
+// Lint checks the file for problems that GNS3 would reject or silently
+// drop when the project is opened.
+func (f *File) Lint() []Problem {
+	var problems []Problem
+	add := func(path, format string, args ...interface{}) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if f.Type != "topology" {
+		add("type", "expected %q, got %q", "topology", f.Type)
+	}
+	if f.Name == "" {
+		add("name", "is required")
+	}
+	if f.ProjectID == "" {
+		add("project_id", "is required")
+	}
+	if f.Revision < CurrentRevision {
+		add("revision", "revision %d must be migrated to %d", f.Revision, CurrentRevision)
+	}
+	if f.Revision > CurrentRevision {
+		add("revision", "revision %d is newer than %d and cannot be written", f.Revision, CurrentRevision)
+	}
+
+	computes := map[string]bool{"local": true, "vm": true}
+	for i, c := range f.Topology.Computes {
+		if c.ComputeID == "" {
+			add(fmt.Sprintf("topology.computes[%d]", i), "compute_id is required")
+		}
+		computes[c.ComputeID] = true
+	}
+
+	nodes := map[string]Node{}
+	names := map[string]bool{}
+	for i, n := range f.Topology.Nodes {
+		path := fmt.Sprintf("topology.nodes[%d]", i)
+		switch {
+		case n.NodeID == "":
+			add(path, "node_id is required")
+		case nodes[n.NodeID].NodeID != "":
+			add(path, "duplicate node_id %s", n.NodeID)
+		}
+		if names[n.Name] {
+			add(path, "duplicate node name %q", n.Name)
+		}
+		if n.NodeType == "" {
+			add(path, "node_type is required")
+		}
+		if !computes[n.ComputeID] {
+			add(path, "unknown compute %q", n.ComputeID)
+		}
+		nodes[n.NodeID] = n
+		names[n.Name] = true
+	}
+
+	links := map[string]bool{}
+	ports := map[string]string{}
+	for i, l := range f.Topology.Links {
+		path := fmt.Sprintf("topology.links[%d]", i)
+		if l.LinkID == "" || links[l.LinkID] {
+			add(path, "missing or duplicate link_id %q", l.LinkID)
+		}
+		links[l.LinkID] = true
+		if len(l.Nodes) != 2 {
+			add(path, "expected 2 nodes, got %d", len(l.Nodes))
+		}
+		for j, ln := range l.Nodes {
+			if _, ok := nodes[ln.NodeID]; !ok {
+				add(fmt.Sprintf("%s.nodes[%d]", path, j), "unknown node %s", ln.NodeID)
+				continue
+			}
+			port := fmt.Sprintf("%s %d/%d", ln.NodeID, ln.AdapterNumber, ln.PortNumber)
+			if other, ok := ports[port]; ok {
+				add(fmt.Sprintf("%s.nodes[%d]", path, j), "port %d/%d of node %q is already used by link %s", ln.AdapterNumber, ln.PortNumber, nodes[ln.NodeID].Name, other)
+			}
+			ports[port] = l.LinkID
+		}
+	}
+
+	drawings := map[string]bool{}
+	for i, d := range f.Topology.Drawings {
+		if d.DrawingID == "" || drawings[d.DrawingID] {
+			add(fmt.Sprintf("topology.drawings[%d]", i), "missing or duplicate drawing_id %q", d.DrawingID)
+		}
+		drawings[d.DrawingID] = true
+	}
+	return problems
+}
@@ -0,0 +1,55 @@
+package gns3file
+
+import (
+	"errors"
+)
+
+// OldestRevision is the oldest revision that Migrate can upgrade, written by
+// GNS3 2.0 and 2.1. Older files must be opened once by a GNS3 server.
+const OldestRevision = 8
+
+// ErrUnsupportedRevision is returned by Migrate for files older than OldestRevision.
+var ErrUnsupportedRevision = errors.New("gns3file: file revision is too old to migrate")
+
+// NeedsMigration returns true if the file has an older revision than CurrentRevision.
+func (f *File) NeedsMigration() bool {
+	return f.Revision < CurrentRevision
+}
+
+// Migrate upgrades the file to CurrentRevision the way GNS3 does when it
+// opens the project.
+func (f *File) Migrate() error {
+	if f.Revision > CurrentRevision {
+		return ErrNewerRevision
+	}
+	if f.Revision < OldestRevision {
+		return ErrUnsupportedRevision
+	}
+	if f.Revision == 8 {
+		migrate8(f)
+	}
+	f.Revision = CurrentRevision
+	return nil
+}
+
+// migrate8 converts GNS3 2.1 files. GNS3 2.2 replaced the acpi_shutdown
+// option of qemu, vmware and virtualbox nodes with on_close.
+func migrate8(f *File) {
+	for _, n := range f.Topology.Nodes {
+		switch n.NodeType {
+		case "qemu", "vmware", "virtualbox":
+		default:
+			continue
+		}
+		acpi, ok := n.Properties["acpi_shutdown"]
+		if !ok {
+			continue
+		}
+		if acpi == true {
+			n.Properties["on_close"] = "shutdown_signal"
+		} else {
+			n.Properties["on_close"] = "power_off"
+		}
+		delete(n.Properties, "acpi_shutdown")
+	}
+}
@@ -0,0 +1,37 @@
+package gns3tests
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestRecordReplaySuite records the suite against the fake server, then
+// replays the cassette, by running this test binary twice.
+func TestRecordReplaySuite(t *testing.T) {
+	for _, name := range []string{"GONS3_SERVER", "GONS3_RECORD", "GONS3_REPLAY"} {
+		if os.Getenv(name) != "" {
+			t.Skipf("Skipping with %v set", name)
+		}
+	}
+	if testing.Short() {
+		t.Skip("Skipping in short mode")
+	}
+
+	dir, err := ioutil.TempDir("", "gons3")
+	if err != nil {
+		t.Fatalf("Error creating directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "suite.json")
+
+	for _, env := range []string{"GONS3_RECORD=" + filename, "GONS3_REPLAY=" + filename} {
+		cmd := exec.Command(os.Args[0], "-test.count=1")
+		cmd.Env = append(os.Environ(), env)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Error running the suite with %v: %v\n%s", env, err, out)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package gns3tests
+
+import (
+	"bytes"
+	"gons3"
+	"strings"
+	"testing"
+)
+
+func TestClientHandles(t *testing.T) {
+	gc := gons3.NewClient(client)
+
+	c := gons3.ProjectCreator{}
+	c.SetName("TestClientHandles")
+	project, err := gc.CreateProject(c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer project.Delete()
+
+	l := gons3.LinkCreator{}
+	nodes := []*gons3.NodeHandle{}
+	for _, name := range []string{"PC1", "PC2"} {
+		n := gons3.NodeCreator{}
+		n.SetName(name)
+		n.SetNodeType("vpcs")
+		n.SetComputeID("local")
+		node, err := project.Nodes().Create(n)
+		if err != nil {
+			t.Fatalf("Error creating node: %v", err)
+		}
+		if node.ProjectID() != project.ID() {
+			t.Errorf("Expected projectID: %v, got %v", project.ID(), node.ProjectID())
+		}
+		l.AddNode(node.ID(), 0, 0)
+		nodes = append(nodes, node)
+	}
+
+	if err := nodes[0].Start(); err != nil {
+		t.Fatalf("Error starting node: %v", err)
+	}
+	if !nodes[0].Model().IsStarted() {
+		t.Errorf("Expected IsStarted(): %v, got %v", true, nodes[0].Model().IsStarted())
+	}
+
+	if err := nodes[1].PutFile("startup.vpc", []byte("ip 10.0.0.1/24\n")); err != nil {
+		t.Fatalf("Error writing node file: %v", err)
+	}
+	if data, err := nodes[1].GetFile("startup.vpc"); err != nil || string(data) != "ip 10.0.0.1/24\n" {
+		t.Errorf("Expected node file: %q, got %q, %v", "ip 10.0.0.1/24\n", data, err)
+	}
+	if err := nodes[1].PutFileFrom("startup.vpc", strings.NewReader("ip 10.0.0.2/24\n"), nil); err != nil {
+		t.Fatalf("Error streaming node file: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := nodes[1].GetFileTo("startup.vpc", &buf, nil); err != nil || buf.String() != "ip 10.0.0.2/24\n" {
+		t.Errorf("Expected node file: %q, got %q, %v", "ip 10.0.0.2/24\n", buf.String(), err)
+	}
+
+	link, err := project.Links().Create(l)
+	if err != nil {
+		t.Fatalf("Error creating link: %v", err)
+	}
+
+	// A handle created from an id loads its model on Refresh
+	byID := gc.Project(project.ID()).Link(link.ID())
+	if err := byID.StartCapture(); err != nil {
+		t.Fatalf("Error starting capture: %v", err)
+	}
+	if !byID.Model().Capturing {
+		t.Errorf("Expected capturing: %v, got %v", true, byID.Model().Capturing)
+	}
+	if link.Model().Capturing {
+		t.Errorf("Expected cached capturing: %v, got %v", false, link.Model().Capturing)
+	}
+	if err := link.Refresh(); err != nil {
+		t.Fatalf("Error refreshing link: %v", err)
+	}
+	if !link.Model().Capturing {
+		t.Errorf("Expected refreshed capturing: %v, got %v", true, link.Model().Capturing)
+	}
+
+	links, err := nodes[1].Links()
+	if err != nil {
+		t.Fatalf("Error getting node links: %v", err)
+	}
+	if len(links) != 1 || links[0].ID() != link.ID() {
+		t.Errorf("Expected links: %v, got %v", link.ID(), links)
+	}
+
+	handle := gc.Project(project.ID())
+	if err := handle.Refresh(); err != nil {
+		t.Fatalf("Error refreshing project: %v", err)
+	}
+	if handle.Model().Name != "TestClientHandles" {
+		t.Errorf("Expected name: %v, got %v", "TestClientHandles", handle.Model().Name)
+	}
+}
@@ -0,0 +1,86 @@
+package gns3tests
+
+import (
+	"gons3"
+	"regexp"
+	"testing"
+)
+
+// idlePCRegexp matches an Idle-PC value, such as 0x60606f54.
+var idlePCRegexp = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+
+func TestIdlePC(t *testing.T) {
+	const image = "c7200-adventerprisek9-mz.124-24.T5.image"
+	if ok, err := hasImage(client, "dynamips", image); err != nil || !ok {
+		t.Skipf("Skipping without the dynamips image %v: %v", image, err)
+	}
+
+	templateID, err := createTemplate(client, map[string]interface{}{"name": "TestIdlePC", "template_type": "dynamips", "platform": "c7200", "image": image, "idlepc": ""})
+	if err != nil {
+		t.Fatalf("Error creating template: %v", err)
+	}
+	defer deleteTemplate(client, templateID)
+
+	c := gons3.ProjectCreator{}
+	c.SetName("TestIdlePC")
+	proj, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, proj.ProjectID)
+	node, err := gons3.CreateNodeFromTemplate(client, proj.ProjectID, templateID, gons3.TemplateUsage{})
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	proposals, err := gons3.GetNodeIdlePCProposals(client, proj.ProjectID, node.NodeID)
+	if err != nil {
+		t.Fatalf("Error getting Idle-PC proposals: %v", err)
+	}
+	for _, proposal := range proposals {
+		if !idlePCRegexp.MatchString(proposal) {
+			t.Errorf("Expected an Idle-PC proposal, got %q", proposal)
+		}
+	}
+	idlePC, err := gons3.AutoIdlePC(client, proj.ProjectID, node.NodeID)
+	if err != nil {
+		t.Fatalf("Error computing Idle-PC: %v", err)
+	}
+	if !idlePCRegexp.MatchString(idlePC) {
+		t.Fatalf("Expected an Idle-PC value, got %q", idlePC)
+	}
+
+	node, err = gons3.SetNodeIdlePC(client, proj.ProjectID, node.NodeID, idlePC)
+	if err != nil {
+		t.Fatalf("Error setting node Idle-PC: %v", err)
+	}
+	if node.Properties["idlepc"] != idlePC {
+		t.Errorf("Expected idlepc: %v, got %v", idlePC, node.Properties["idlepc"])
+	}
+
+	if _, err := gons3.SetTemplateIdlePC(client, templateID, idlePC); err != nil {
+		t.Fatalf("Error setting template Idle-PC: %v", err)
+	}
+	node, err = gons3.CreateNodeFromTemplate(client, proj.ProjectID, templateID, gons3.TemplateUsage{})
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+	if node.Properties["idlepc"] != idlePC {
+		t.Errorf("Expected idlepc: %v, got %v", idlePC, node.Properties["idlepc"])
+	}
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	pc, err := gons3.CreateNode(client, proj.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+	if _, err := gons3.AutoIdlePC(client, proj.ProjectID, pc.NodeID); !gons3.IsNotFound(err) {
+		t.Errorf("Expected IsNotFound: %v, got %v", true, err)
+	}
+	if _, err := gons3.SetTemplateIdlePC(client, "19021f99-e36f-394d-b4a1-8aaa902ab9cc", idlePC); !gons3.IsConflict(err) {
+		t.Errorf("Expected IsConflict: %v, got %v", true, err)
+	}
+}
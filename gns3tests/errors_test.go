@@ -0,0 +1,73 @@
+package gns3tests
+
+import (
+	"errors"
+	"gons3"
+	"testing"
+)
+
+func TestServerErrorNotFound(t *testing.T) {
+	_, err := gons3.GetProject(client, "00000000-0000-0000-0000-000000000000")
+	if !gons3.IsNotFound(err) {
+		t.Fatalf("Expected IsNotFound: %v, got %v", true, err)
+	}
+	if gons3.IsConflict(err) || gons3.IsValidation(err) {
+		t.Errorf("Expected only IsNotFound to match, got %v", err)
+	}
+
+	var serverErr gons3.ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("Expected ServerError, got %v", err)
+	}
+	if serverErr.GetStatusCode() != 404 {
+		t.Errorf("Expected status code: %v, got %v", 404, serverErr.GetStatusCode())
+	}
+	if serverErr.GetMethod() != "GET" {
+		t.Errorf("Expected method: %v, got %v", "GET", serverErr.GetMethod())
+	}
+	if serverErr.GetURL() != client.GetSchemeAuthority()+"/v2/projects/00000000-0000-0000-0000-000000000000" {
+		t.Errorf("Expected URL of missing project, got %v", serverErr.GetURL())
+	}
+	if len(serverErr.GetBody()) == 0 {
+		t.Errorf("Expected a response body")
+	}
+}
+
+func TestServerErrorConflict(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestServerErrorConflict")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	_, err = gons3.CreateProject(client, c)
+	if !gons3.IsConflict(err) {
+		t.Fatalf("Expected IsConflict: %v, got %v", true, err)
+	}
+}
+
+func TestServerErrorValidation(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetZoom(50)
+	_, err := gons3.CreateProject(client, c)
+	if !gons3.IsValidation(err) {
+		t.Fatalf("Expected IsValidation: %v, got %v", true, err)
+	}
+
+	var serverErr gons3.ServerError
+	if !errors.As(err, &serverErr) {
+		t.Fatalf("Expected ServerError, got %v", err)
+	}
+	v := serverErr.GetValidation()
+	if v == nil {
+		t.Fatalf("Expected validation details in %v", serverErr.GetFullMessage())
+	}
+	if v.Validator != "required" {
+		t.Errorf("Expected validator: %v, got %v", "required", v.Validator)
+	}
+	if v.JSONPath != "$.name" {
+		t.Errorf("Expected JSON path: %v, got %v", "$.name", v.JSONPath)
+	}
+}
@@ -0,0 +1,111 @@
+package gns3tests
+
+import (
+	"bytes"
+	"errors"
+	"gons3"
+	"testing"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (f failingWriter) Write([]byte) (int, error) {
+	return 0, f.err
+}
+
+func TestStreamProjectFile(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestStreamProjectFile")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	var sent, sentTotal int64
+	progress := func(transferred, total int64) {
+		sent, sentTotal = transferred, total
+	}
+	if err := gons3.WriteProjectFileFrom(client, ci.ProjectID, "disk.img", bytes.NewReader(data), progress); err != nil {
+		t.Fatalf("Error writing project file: %v", err)
+	}
+	if sent != int64(len(data)) || sentTotal != int64(len(data)) {
+		t.Errorf("Expected progress: %v of %v, got %v of %v", len(data), len(data), sent, sentTotal)
+	}
+
+	var buf bytes.Buffer
+	var received, receivedTotal int64
+	decreased := false
+	progress = func(transferred, total int64) {
+		if transferred < received {
+			decreased = true
+		}
+		received, receivedTotal = transferred, total
+	}
+	if err := gons3.ReadProjectFileTo(client, ci.ProjectID, "disk.img", &buf, progress); err != nil {
+		t.Fatalf("Error reading project file: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("Expected %v bytes, got %v", len(data), buf.Len())
+	}
+	if received != int64(len(data)) || (receivedTotal != int64(len(data)) && receivedTotal != -1) {
+		t.Errorf("Expected progress: %v of %v, got %v of %v", len(data), len(data), received, receivedTotal)
+	}
+	if decreased {
+		t.Errorf("Expected progress to never go down")
+	}
+
+	writeErr := errors.New("disk full")
+	err = gons3.ReadProjectFileTo(client, ci.ProjectID, "disk.img", failingWriter{writeErr}, nil)
+	if !errors.Is(err, gons3.ErrFailedToWriteResult) || !errors.Is(err, writeErr) || errors.Is(err, gons3.ErrFailedToReadResult) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrFailedToWriteResult, err)
+	}
+
+	if err := gons3.ReadProjectFileTo(client, ci.ProjectID, "missing.img", &buf, nil); !gons3.IsNotFound(err) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrNotFound, err)
+	}
+}
+
+func TestUnicodeFilepaths(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestUnicodeFilepaths")
+	proj, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, proj.ProjectID)
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	node, err := gons3.CreateNode(client, proj.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	names := []string{"notes #1?.txt", "日本語/設定.cfg", "émoji 🚀.txt", "a%2Fb.txt"}
+	for _, name := range names {
+		if err := gons3.WriteProjectFile(client, proj.ProjectID, name, []byte(name)); err != nil {
+			t.Fatalf("Error writing project file %q: %v", name, err)
+		}
+		if err := gons3.WriteNodeFile(client, proj.ProjectID, node.NodeID, name, []byte(name)); err != nil {
+			t.Fatalf("Error writing node file %q: %v", name, err)
+		}
+	}
+	for _, name := range names {
+		data, err := gons3.ReadProjectFile(client, proj.ProjectID, name)
+		if err != nil || string(data) != name {
+			t.Errorf("Expected project file %q, got %q, %v", name, data, err)
+		}
+		data, err = gons3.ReadNodeFile(client, proj.ProjectID, node.NodeID, name)
+		if err != nil || string(data) != name {
+			t.Errorf("Expected node file %q, got %q, %v", name, data, err)
+		}
+	}
+	if _, err := gons3.ReadProjectFile(client, proj.ProjectID, "notes"); !gons3.IsNotFound(err) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrNotFound, err)
+	}
+}
@@ -1,10 +1,32 @@
 package gns3tests
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"gons3"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
 )
 
-var client = gons3.GNS3HTTPClient{}
+var client gons3.GNS3Client = gons3.GNS3HTTPClient{}
+
+// serverClient creates a client for the GNS3 server at rawURL, such as "http://127.0.0.1:3080".
+func serverClient(rawURL string) (gons3.GNS3Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	c := gons3.GNS3HTTPClient{Scheme: u.Scheme, Hostname: u.Hostname()}
+	if u.Port() != "" {
+		if c.Port, err = strconv.Atoi(u.Port()); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
 
 func deleteProjectByName(g gons3.GNS3Client, name string) error {
 	pjs, err := gons3.GetProjects(g)
@@ -18,3 +40,78 @@ func deleteProjectByName(g gons3.GNS3Client, name string) error {
 	}
 	return nil
 }
+
+// createTemplate creates a template, such as a dynamips router, and returns
+// its id. The library only reads and updates templates, so the request is
+// sent with the client directly.
+func createTemplate(g gons3.GNS3Client, template map[string]interface{}) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", g.GetSchemeAuthority()+"/v2/templates", bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := g.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 201 {
+		return "", fmt.Errorf("status code %v: %s", resp.StatusCode, data)
+	}
+	created := gons3.Template{}
+	if err := json.Unmarshal(data, &created); err != nil {
+		return "", err
+	}
+	return created.TemplateID, nil
+}
+
+// deleteTemplate deletes a template created by createTemplate.
+func deleteTemplate(g gons3.GNS3Client, templateID string) error {
+	req, err := http.NewRequest("DELETE", g.GetSchemeAuthority()+"/v2/templates/"+url.PathEscape(templateID), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := g.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// hasImage returns true if the local compute has the image for emulator,
+// such as dynamips or qemu. Tests that need an image skip without it.
+func hasImage(g gons3.GNS3Client, emulator, filename string) (bool, error) {
+	path := "/v2/computes/local/" + url.PathEscape(emulator) + "/images"
+	req, err := http.NewRequest("GET", g.GetSchemeAuthority()+path, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := g.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("status code %v", resp.StatusCode)
+	}
+	images := []struct {
+		Filename string `json:"filename"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&images); err != nil {
+		return false, err
+	}
+	for _, image := range images {
+		if image.Filename == filename {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,117 @@
+package gns3tests
+
+import (
+	"gons3"
+	"testing"
+)
+
+func TestCreateLink(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestCreateLink")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	nodes := []gons3.Node{}
+	for _, name := range []string{"PC1", "PC2"} {
+		n := gons3.NodeCreator{}
+		n.SetName(name)
+		n.SetNodeType("vpcs")
+		n.SetComputeID("local")
+		node, err := gons3.CreateNode(client, ci.ProjectID, n)
+		if err != nil {
+			t.Fatalf("Error creating node: %v", err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	l := gons3.LinkCreator{}
+	l.AddNode(nodes[0].NodeID, 0, 0)
+	l.AddNode(nodes[1].NodeID, 0, 0)
+	link, err := gons3.CreateLink(client, ci.ProjectID, l)
+	if err != nil {
+		t.Fatalf("Error creating link: %v", err)
+	}
+	if !link.HasNode(nodes[0].NodeID) || !link.HasNode(nodes[1].NodeID) {
+		t.Errorf("Expected link between %v and %v, got %v", nodes[0].NodeID, nodes[1].NodeID, link.Nodes)
+	}
+
+	if _, err := gons3.CreateLink(client, ci.ProjectID, l); !gons3.IsConflict(err) {
+		t.Errorf("Expected IsConflict: %v, got %v", true, err)
+	}
+
+	links, err := gons3.GetNodeLinks(client, ci.ProjectID, nodes[0].NodeID)
+	if err != nil {
+		t.Fatalf("Error getting node links: %v", err)
+	}
+	if len(links) != 1 || links[0].LinkID != link.LinkID {
+		t.Errorf("Expected links: %v, got %v", link.LinkID, links)
+	}
+
+	u := gons3.LinkUpdater{}
+	u.SetSuspend(true)
+	link, err = gons3.UpdateLink(client, ci.ProjectID, link.LinkID, u)
+	if err != nil {
+		t.Fatalf("Error updating link: %v", err)
+	}
+	if !link.Suspend {
+		t.Errorf("Expected suspend: %v, got %v", true, link.Suspend)
+	}
+
+	if err := gons3.DeleteLink(client, ci.ProjectID, link.LinkID); err != nil {
+		t.Fatalf("Error deleting link: %v", err)
+	}
+	if _, err := gons3.GetLink(client, ci.ProjectID, link.LinkID); !gons3.IsNotFound(err) {
+		t.Errorf("Expected IsNotFound: %v, got %v", true, err)
+	}
+}
+
+func TestLinkCapture(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestLinkCapture")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	l := gons3.LinkCreator{}
+	for _, name := range []string{"PC1", "PC2"} {
+		n := gons3.NodeCreator{}
+		n.SetName(name)
+		n.SetNodeType("vpcs")
+		n.SetComputeID("local")
+		node, err := gons3.CreateNode(client, ci.ProjectID, n)
+		if err != nil {
+			t.Fatalf("Error creating node: %v", err)
+		}
+		l.AddNode(node.NodeID, 0, 0)
+	}
+	link, err := gons3.CreateLink(client, ci.ProjectID, l)
+	if err != nil {
+		t.Fatalf("Error creating link: %v", err)
+	}
+
+	capture := gons3.LinkCapture{}
+	capture.SetCaptureFileName("test.pcap")
+	link, err = gons3.StartLinkCapture(client, ci.ProjectID, link.LinkID, capture)
+	if err != nil {
+		t.Fatalf("Error starting capture: %v", err)
+	}
+	if !link.Capturing {
+		t.Errorf("Expected capturing: %v, got %v", true, link.Capturing)
+	}
+	if link.CaptureFileName != "test.pcap" {
+		t.Errorf("Expected captureFileName: %v, got %v", "test.pcap", link.CaptureFileName)
+	}
+
+	link, err = gons3.StopLinkCapture(client, ci.ProjectID, link.LinkID)
+	if err != nil {
+		t.Fatalf("Error stopping capture: %v", err)
+	}
+	if link.Capturing {
+		t.Errorf("Expected capturing: %v, got %v", false, link.Capturing)
+	}
+}
@@ -0,0 +1,54 @@
+package gns3tests
+
+import (
+	"fmt"
+	"gons3/gons3test"
+	"os"
+	"testing"
+)
+
+// TestMain runs the tests against the GNS3 server in GONS3_SERVER, such as
+// "http://127.0.0.1:3080", or against an in-process fake server when unset.
+// GONS3_RECORD records the interactions with the server to a cassette file,
+// and GONS3_REPLAY replays a cassette file instead of using any server.
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	if filename := os.Getenv("GONS3_REPLAY"); filename != "" {
+		replayer, err := gons3test.LoadReplayer(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load cassette: %v\n", err)
+			return 2
+		}
+		client = replayer
+		return m.Run()
+	}
+
+	if rawURL := os.Getenv("GONS3_SERVER"); rawURL != "" {
+		c, err := serverClient(rawURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid GONS3_SERVER: %v\n", err)
+			return 2
+		}
+		client = c
+	} else {
+		server := gons3test.NewServer()
+		defer server.Close()
+		client = server.Client()
+	}
+
+	filename := os.Getenv("GONS3_RECORD")
+	if filename == "" {
+		return m.Run()
+	}
+	recorder := gons3test.NewRecorder(client)
+	client = recorder
+	code := m.Run()
+	if err := recorder.Save(filename); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to save cassette: %v\n", err)
+		return 2
+	}
+	return code
+}
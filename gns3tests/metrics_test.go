@@ -0,0 +1,50 @@
+package gns3tests
+
+import (
+	"encoding/json"
+	"gons3"
+	"testing"
+)
+
+func TestMetricsClient(t *testing.T) {
+	m := &gons3.Metrics{}
+	mc := gons3.MetricsClient{Client: client, Metrics: m}
+
+	c := gons3.ProjectCreator{}
+	c.SetName("TestMetricsClient")
+	ci, err := gons3.CreateProject(mc, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	if _, err := gons3.GetProject(mc, ci.ProjectID); err != nil {
+		t.Fatalf("Error getting project: %v", err)
+	}
+	if _, err := gons3.GetProject(mc, "00000000-0000-0000-0000-000000000000"); err == nil {
+		t.Fatalf("Expected error getting missing project")
+	}
+
+	stats := map[string]gons3.EndpointStats{}
+	for _, s := range m.Snapshot() {
+		stats[s.Method+" "+s.Endpoint] = s
+	}
+	if s := stats["POST /v2/projects"]; s.Count != 1 {
+		t.Errorf("Expected POST /v2/projects count: %v, got %v", 1, s.Count)
+	}
+	s := stats["GET /v2/projects/{id}"]
+	if s.Count != 2 {
+		t.Errorf("Expected GET /v2/projects/{id} count: %v, got %v", 2, s.Count)
+	}
+	if s.Errors[gons3.ErrorClassClientError] != 1 {
+		t.Errorf("Expected client errors: %v, got %v", 1, s.Errors[gons3.ErrorClassClientError])
+	}
+
+	var published []gons3.EndpointStats
+	if err := json.Unmarshal([]byte(m.String()), &published); err != nil {
+		t.Fatalf("Error unmarshaling metrics: %v", err)
+	}
+	if len(published) != 2 {
+		t.Errorf("Expected published endpoints: %v, got %v", 2, len(published))
+	}
+}
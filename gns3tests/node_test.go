@@ -0,0 +1,365 @@
+package gns3tests
+
+import (
+	"bytes"
+	"gons3"
+	"gons3/gons3test"
+	"strings"
+	"testing"
+)
+
+func TestCreateNode(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestCreateNode")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	n.SetPosition(10, 20, 1)
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	if node.Name != "PC1" {
+		t.Errorf("Expected name: %v, got %v", "PC1", node.Name)
+	}
+	if node.NodeType != "vpcs" {
+		t.Errorf("Expected nodeType: %v, got %v", "vpcs", node.NodeType)
+	}
+	if node.X != 10 || node.Y != 20 {
+		t.Errorf("Expected position: %v,%v, got %v,%v", 10, 20, node.X, node.Y)
+	}
+	if !node.IsStopped() {
+		t.Errorf("Expected IsStopped(): %v, got %v", true, node.IsStopped())
+	}
+	if _, ok := node.PortByName("Ethernet0"); !ok {
+		t.Errorf("Expected port: %v, got %v", "Ethernet0", node.Ports)
+	}
+
+	nodes, err := gons3.GetNodes(client, ci.ProjectID)
+	if err != nil {
+		t.Fatalf("Error getting nodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].NodeID != node.NodeID {
+		t.Errorf("Expected nodes: %v, got %v", node.NodeID, nodes)
+	}
+}
+
+func TestUpdateNode(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestUpdateNode")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	u := gons3.NodeUpdater{}
+	u.SetName("PC2")
+	u.SetLocked(true)
+	node, err = gons3.UpdateNode(client, ci.ProjectID, node.NodeID, u)
+	if err != nil {
+		t.Fatalf("Error updating node: %v", err)
+	}
+	if node.Name != "PC2" {
+		t.Errorf("Expected name: %v, got %v", "PC2", node.Name)
+	}
+	if node.Locked != true {
+		t.Errorf("Expected locked: %v, got %v", true, node.Locked)
+	}
+}
+
+func TestStartStopNode(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestStartStopNode")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	node, err = gons3.StartNode(client, ci.ProjectID, node.NodeID)
+	if err != nil {
+		t.Fatalf("Error starting node: %v", err)
+	}
+	if !node.IsStarted() {
+		t.Errorf("Expected IsStarted(): %v, got %v", true, node.IsStarted())
+	}
+
+	node, err = gons3.StopNode(client, ci.ProjectID, node.NodeID)
+	if err != nil {
+		t.Fatalf("Error stopping node: %v", err)
+	}
+	if !node.IsStopped() {
+		t.Errorf("Expected IsStopped(): %v, got %v", true, node.IsStopped())
+	}
+}
+
+func TestDeleteNode(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestDeleteNode")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	if err := gons3.DeleteNode(client, ci.ProjectID, node.NodeID); err != nil {
+		t.Fatalf("Error deleting node: %v", err)
+	}
+	if _, err := gons3.GetNode(client, ci.ProjectID, node.NodeID); !gons3.IsNotFound(err) {
+		t.Errorf("Expected IsNotFound: %v, got %v", true, err)
+	}
+}
+
+func TestReadWriteNodeFile(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestReadWriteNodeFile")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	err = gons3.WriteNodeFile(client, ci.ProjectID, node.NodeID, "startup.vpc", []byte("ip 10.0.0.1/24\n"))
+	if err != nil {
+		t.Fatalf("Error writing node file: %v", err)
+	}
+	data, err := gons3.ReadNodeFile(client, ci.ProjectID, node.NodeID, "startup.vpc")
+	if err != nil {
+		t.Fatalf("Error reading node file: %v", err)
+	}
+	if string(data) != "ip 10.0.0.1/24\n" {
+		t.Errorf("Expected data: %q, got %q", "ip 10.0.0.1/24\n", data)
+	}
+
+	var sent int64
+	err = gons3.WriteNodeFileFrom(client, ci.ProjectID, node.NodeID, "startup.vpc", strings.NewReader("ip 10.0.0.2/24\n"), func(transferred, total int64) {
+		sent = transferred
+	})
+	if err != nil {
+		t.Fatalf("Error streaming node file: %v", err)
+	}
+	if sent != 15 {
+		t.Errorf("Expected sent: %v, got %v", 15, sent)
+	}
+	var buf bytes.Buffer
+	var received int64
+	err = gons3.ReadNodeFileTo(client, ci.ProjectID, node.NodeID, "startup.vpc", &buf, func(transferred, total int64) {
+		received = transferred
+	})
+	if err != nil {
+		t.Fatalf("Error streaming node file: %v", err)
+	}
+	if received != 15 {
+		t.Errorf("Expected received: %v, got %v", 15, received)
+	}
+	if buf.String() != "ip 10.0.0.2/24\n" {
+		t.Errorf("Expected data: %q, got %q", "ip 10.0.0.2/24\n", buf.String())
+	}
+
+	if _, err := gons3.ReadNodeFile(client, ci.ProjectID, node.NodeID, "missing.txt"); !gons3.IsNotFound(err) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrNotFound, err)
+	}
+	if _, err := gons3.ReadNodeFile(client, ci.ProjectID, node.NodeID, ""); err != gons3.ErrEmptyFilepath {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrEmptyFilepath, err)
+	}
+}
+
+func TestDuplicateNode(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestDuplicateNode")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	n.SetPosition(10, 20, 1)
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+	err = gons3.WriteNodeFile(client, ci.ProjectID, node.NodeID, "startup.vpc", []byte("ip 10.0.0.1/24\n"))
+	if err != nil {
+		t.Fatalf("Error writing node file: %v", err)
+	}
+
+	dup, err := gons3.DuplicateNode(client, ci.ProjectID, node.NodeID, 110, 20, 1)
+	if err != nil {
+		t.Fatalf("Error duplicating node: %v", err)
+	}
+	if dup.NodeID == node.NodeID || dup.Name != "PC2" {
+		t.Errorf("Expected new node PC2, got %v %v", dup.NodeID, dup.Name)
+	}
+	if dup.X != 110 || dup.Y != 20 {
+		t.Errorf("Expected position: %v,%v, got %v,%v", 110, 20, dup.X, dup.Y)
+	}
+	data, err := gons3.ReadNodeFile(client, ci.ProjectID, dup.NodeID, "startup.vpc")
+	if err != nil {
+		t.Fatalf("Error reading duplicated node file: %v", err)
+	}
+	if string(data) != "ip 10.0.0.1/24\n" {
+		t.Errorf("Expected data: %q, got %q", "ip 10.0.0.1/24\n", data)
+	}
+
+	clones, err := gons3.CloneNodes(client, ci.ProjectID, node.NodeID, gons3.CloneOptions{
+		Count:      3,
+		NameFormat: "leaf%d",
+		FirstIndex: 1,
+		Columns:    2,
+	})
+	if err != nil {
+		t.Fatalf("Error cloning node: %v", err)
+	}
+	expected := []struct {
+		name string
+		x, y int
+	}{{"leaf1", 10, 120}, {"leaf2", 110, 120}, {"leaf3", 10, 220}}
+	if len(clones) != len(expected) {
+		t.Fatalf("Expected clones: %v, got %v", len(expected), len(clones))
+	}
+	for i, e := range expected {
+		if clones[i].Name != e.name || clones[i].X != e.x || clones[i].Y != e.y {
+			t.Errorf("Expected clone %v at %v,%v, got %v at %v,%v", e.name, e.x, e.y, clones[i].Name, clones[i].X, clones[i].Y)
+		}
+	}
+
+	// A copy that cannot be renamed is returned so the caller can remove it
+	fc := gons3test.NewFaultClient(client)
+	fault := gons3test.StatusFault("PUT", "/v2/projects/{id}/nodes/{id}", 500, "rename failed")
+	fault.Times = 1
+	fc.Inject(fault)
+	clones, err = gons3.CloneNodes(fc, ci.ProjectID, node.NodeID, gons3.CloneOptions{Count: 2, NameFormat: "spine%d"})
+	if err == nil {
+		t.Fatalf("Expected the rename to fail")
+	}
+	if len(clones) != 1 {
+		t.Fatalf("Expected clones: %v, got %v", 1, len(clones))
+	}
+	if _, err := gons3.GetNode(client, ci.ProjectID, clones[0].NodeID); err != nil {
+		t.Errorf("Expected the returned clone to exist, got %v", err)
+	}
+
+	if _, err := gons3.StartNode(client, ci.ProjectID, node.NodeID); err != nil {
+		t.Fatalf("Error starting node: %v", err)
+	}
+	if _, err := gons3.DuplicateNode(client, ci.ProjectID, node.NodeID, 0, 0, 1); !gons3.IsConflict(err) {
+		t.Errorf("Expected IsConflict: %v, got %v", true, err)
+	}
+}
+
+func TestIsolateNode(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestIsolateNode")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	nodes := []gons3.Node{}
+	for _, nt := range [][]string{{"SW1", "ethernet_switch"}, {"PC1", "vpcs"}, {"PC2", "vpcs"}} {
+		n := gons3.NodeCreator{}
+		n.SetName(nt[0])
+		n.SetNodeType(nt[1])
+		n.SetComputeID("local")
+		node, err := gons3.CreateNode(client, ci.ProjectID, n)
+		if err != nil {
+			t.Fatalf("Error creating node: %v", err)
+		}
+		nodes = append(nodes, node)
+	}
+	links := []gons3.Link{}
+	for i, pc := range nodes[1:] {
+		l := gons3.LinkCreator{}
+		l.AddNode(nodes[0].NodeID, 0, i)
+		l.AddNode(pc.NodeID, 0, 0)
+		link, err := gons3.CreateLink(client, ci.ProjectID, l)
+		if err != nil {
+			t.Fatalf("Error creating link: %v", err)
+		}
+		links = append(links, link)
+	}
+
+	suspended := func() []bool {
+		t.Helper()
+		result := []bool{}
+		for _, l := range links {
+			link, err := gons3.GetLink(client, ci.ProjectID, l.LinkID)
+			if err != nil {
+				t.Fatalf("Error getting link: %v", err)
+			}
+			result = append(result, link.Suspend)
+		}
+		return result
+	}
+
+	if err := gons3.IsolateNode(client, ci.ProjectID, nodes[1].NodeID); err != nil {
+		t.Fatalf("Error isolating node: %v", err)
+	}
+	if s := suspended(); !s[0] || s[1] {
+		t.Errorf("Expected suspended links: %v, got %v", []bool{true, false}, s)
+	}
+
+	if err := gons3.IsolateNode(client, ci.ProjectID, nodes[0].NodeID); err != nil {
+		t.Fatalf("Error isolating node: %v", err)
+	}
+	if err := gons3.UnisolateNode(client, ci.ProjectID, nodes[0].NodeID); err != nil {
+		t.Fatalf("Error unisolating node: %v", err)
+	}
+	if s := suspended(); s[0] || s[1] {
+		t.Errorf("Expected suspended links: %v, got %v", []bool{false, false}, s)
+	}
+
+	if err := gons3.IsolateNode(client, ci.ProjectID, "00000000-0000-0000-0000-000000000000"); !gons3.IsNotFound(err) {
+		t.Errorf("Expected IsNotFound: %v, got %v", true, err)
+	}
+}
@@ -0,0 +1,96 @@
+package gns3tests
+
+import (
+	"errors"
+	"gons3"
+	"testing"
+)
+
+func TestNodeProperties(t *testing.T) {
+	c := gons3.ProjectCreator{}
+	c.SetName("TestNodeProperties")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("Linux1")
+	n.SetComputeID("local")
+	n.SetNodeProperties(gons3.QemuProperties{
+		RAM:          gons3.Int(2048),
+		CPUs:         gons3.Int(2),
+		Adapters:     gons3.Int(3),
+		HdaDiskImage: gons3.String("debian-12.qcow2"),
+		LinkedClone:  gons3.Bool(false),
+	})
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+	if node.NodeType != "qemu" || len(node.Ports) != 3 {
+		t.Errorf("Expected qemu node with %v ports, got %v with %v", 3, node.NodeType, len(node.Ports))
+	}
+
+	u := gons3.NodeUpdater{}
+	u.SetNodeProperties(gons3.QemuProperties{RAM: gons3.Int(4096), Usage: gons3.String("")})
+	node, err = gons3.UpdateNode(client, ci.ProjectID, node.NodeID, u)
+	if err != nil {
+		t.Fatalf("Error updating node: %v", err)
+	}
+	properties, err := node.DecodeProperties()
+	if err != nil {
+		t.Fatalf("Error decoding properties: %v", err)
+	}
+	qemu, ok := properties.(*gons3.QemuProperties)
+	if !ok {
+		t.Fatalf("Expected *gons3.QemuProperties, got %T", properties)
+	}
+	if qemu.RAM == nil || *qemu.RAM != 4096 || qemu.CPUs == nil || *qemu.CPUs != 2 {
+		t.Errorf("Expected updated qemu properties, got %+v", qemu)
+	}
+	if qemu.LinkedClone == nil || *qemu.LinkedClone {
+		t.Errorf("Expected linked_clone: %v, got %v", false, qemu.LinkedClone)
+	}
+	if qemu.Usage == nil || *qemu.Usage != "" {
+		t.Errorf("Expected usage: %q, got %v", "", qemu.Usage)
+	}
+
+	n = gons3.NodeCreator{}
+	n.SetName("Alpine1")
+	n.SetComputeID("local")
+	n.SetNodeProperties(gons3.DockerProperties{
+		Image:        gons3.String("alpine:latest"),
+		Environment:  gons3.String("A=1\nB=2"),
+		StartCommand: gons3.String("/bin/sh"),
+		ExtraVolumes: []string{},
+	})
+	node, err = gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+	properties, err = node.DecodeProperties()
+	if err != nil {
+		t.Fatalf("Error decoding properties: %v", err)
+	}
+	docker, ok := properties.(*gons3.DockerProperties)
+	if !ok || docker.Image == nil || *docker.Image != "alpine:latest" || docker.Environment == nil || *docker.Environment != "A=1\nB=2" {
+		t.Errorf("Expected docker properties, got %#v", properties)
+	}
+	if ok && (docker.ExtraVolumes == nil || len(docker.ExtraVolumes) != 0) {
+		t.Errorf("Expected empty extra_volumes, got %#v", docker.ExtraVolumes)
+	}
+
+	n = gons3.NodeCreator{}
+	n.SetName("SW1")
+	n.SetNodeType("ethernet_switch")
+	n.SetComputeID("local")
+	node, err = gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+	if _, err := node.DecodeProperties(); !errors.Is(err, gons3.ErrUnsupportedNodeType) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrUnsupportedNodeType, err)
+	}
+}
@@ -0,0 +1,93 @@
+package gns3tests
+
+import (
+	"errors"
+	"gons3"
+	"testing"
+)
+
+func TestQemuDiskImages(t *testing.T) {
+	const image = "debian-12.qcow2"
+	if ok, err := hasImage(client, "qemu", image); err != nil || !ok {
+		t.Skipf("Skipping without the qemu image %v: %v", image, err)
+	}
+
+	c := gons3.ProjectCreator{}
+	c.SetName("TestQemuDiskImages")
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	n := gons3.NodeCreator{}
+	n.SetName("Linux1")
+	n.SetNodeType("qemu")
+	n.SetComputeID("local")
+	n.SetNodeProperty("hda_disk_image", image)
+	n.SetNodeProperty("hda_disk_interface", "virtio")
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		t.Fatalf("Error creating node: %v", err)
+	}
+
+	d := gons3.QemuDiskImageCreator{}
+	d.SetFormat("qcow2")
+	d.SetSize(1024)
+	d.SetLazyRefcounts(true)
+	if err := gons3.CreateQemuDiskImage(client, ci.ProjectID, node.NodeID, "scratch.qcow2", d); err != nil {
+		t.Fatalf("Error creating disk image: %v", err)
+	}
+	if err := gons3.CreateQemuDiskImage(client, ci.ProjectID, node.NodeID, "scratch.qcow2", d); !gons3.IsConflict(err) {
+		t.Errorf("Expected IsConflict: %v, got %v", true, err)
+	}
+	if err := gons3.ExtendQemuDiskImage(client, ci.ProjectID, node.NodeID, "scratch.qcow2", 512); err != nil {
+		t.Fatalf("Error extending disk image: %v", err)
+	}
+	if err := gons3.ExtendQemuDiskImage(client, ci.ProjectID, node.NodeID, "missing.qcow2", 512); !gons3.IsNotFound(err) {
+		t.Errorf("Expected IsNotFound: %v, got %v", true, err)
+	}
+	for _, name := range []string{"../scratch.qcow2", "/tmp/scratch.qcow2"} {
+		if err := gons3.CreateQemuDiskImage(client, ci.ProjectID, node.NodeID, name, d); !errors.Is(err, gons3.ErrInvalidFilepath) {
+			t.Errorf("%v: Expected error: %v, got %v", name, gons3.ErrInvalidFilepath, err)
+		}
+		if err := gons3.ExtendQemuDiskImage(client, ci.ProjectID, node.NodeID, name, 512); !errors.Is(err, gons3.ErrInvalidFilepath) {
+			t.Errorf("%v: Expected error: %v, got %v", name, gons3.ErrInvalidFilepath, err)
+		}
+	}
+
+	if _, err := gons3.AttachQemuDisk(client, ci.ProjectID, node.NodeID, "hdb", "scratch.qcow2", "virtio"); err != nil {
+		t.Fatalf("Error attaching disk: %v", err)
+	}
+	disks, err := gons3.GetQemuDisks(client, ci.ProjectID, node.NodeID)
+	if err != nil {
+		t.Fatalf("Error getting disks: %v", err)
+	}
+	expected := []gons3.QemuDisk{
+		{Slot: "hda", Image: image, Interface: "virtio"},
+		{Slot: "hdb", Image: "scratch.qcow2", Interface: "virtio"},
+	}
+	// Servers also report the md5sum of the images
+	if len(disks) != len(expected) {
+		t.Fatalf("Expected disks: %v, got %v", expected, disks)
+	}
+	for i, e := range expected {
+		if disks[i].Slot != e.Slot || disks[i].Image != e.Image || disks[i].Interface != e.Interface {
+			t.Errorf("Expected disk: %v, got %v", e, disks[i])
+		}
+	}
+	if _, err := gons3.AttachQemuDisk(client, ci.ProjectID, node.NodeID, "hde", "scratch.qcow2", ""); !errors.Is(err, gons3.ErrInvalidDiskSlot) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrInvalidDiskSlot, err)
+	}
+	if _, err := gons3.DetachQemuDisk(client, ci.ProjectID, node.NodeID, "cdrom"); !errors.Is(err, gons3.ErrInvalidDiskSlot) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrInvalidDiskSlot, err)
+	}
+
+	node, err = gons3.DetachQemuDisk(client, ci.ProjectID, node.NodeID, "hdb")
+	if err != nil {
+		t.Fatalf("Error detaching disk: %v", err)
+	}
+	if disks := node.QemuDisks(); len(disks) != 1 || disks[0].Slot != "hda" {
+		t.Errorf("Expected disks: %v, got %v", expected[:1], disks)
+	}
+}
@@ -0,0 +1,130 @@
+package gns3tests
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	errs       []error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+type testSpanKey struct{}
+
+func (tr *testTracer) Start(ctx context.Context, name string) (context.Context, gons3.Span) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	s := &testSpan{name: name, attributes: map[string]interface{}{}}
+	tr.spans = append(tr.spans, s)
+	return context.WithValue(ctx, testSpanKey{}, s), s
+}
+
+func (tr *testTracer) Inject(ctx context.Context, header http.Header) {
+	if s, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		header.Set("X-Test-Span", s.name)
+	}
+}
+
+type headerRecorder struct {
+	gons3.GNS3Client
+	headers []http.Header
+}
+
+func (h *headerRecorder) Do(req *http.Request) (*http.Response, error) {
+	h.headers = append(h.headers, req.Header)
+	return h.GNS3Client.Do(req)
+}
+
+func TestTracingClient(t *testing.T) {
+	tr := &testTracer{}
+	hr := &headerRecorder{GNS3Client: client}
+	tc := gons3.TracingClient{Client: hr, Tracer: tr}.WithContext(context.Background())
+
+	c := gons3.ProjectCreator{}
+	c.SetName("TestTracingClient")
+	ci, err := gons3.CreateProject(tc, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	missingID := "00000000-0000-0000-0000-000000000000"
+	if _, err := gons3.GetProject(tc, missingID); err == nil {
+		t.Fatalf("Expected error getting missing project")
+	}
+
+	if len(tr.spans) != 2 {
+		t.Fatalf("Expected spans: %v, got %v", 2, len(tr.spans))
+	}
+	for i, s := range tr.spans {
+		if !s.ended {
+			t.Errorf("Expected span %v to be ended", s.name)
+		}
+		if h := hr.headers[i].Get("X-Test-Span"); h != s.name {
+			t.Errorf("Expected propagated header: %v, got %v", s.name, h)
+		}
+	}
+
+	s := tr.spans[1]
+	if s.name != "GET /v2/projects/{id}" {
+		t.Errorf("Expected span name: %v, got %v", "GET /v2/projects/{id}", s.name)
+	}
+	if s.attributes["project_id"] != missingID {
+		t.Errorf("Expected project_id: %v, got %v", missingID, s.attributes["project_id"])
+	}
+	if s.attributes["http.status_code"] != 404 {
+		t.Errorf("Expected status code: %v, got %v", 404, s.attributes["http.status_code"])
+	}
+	if s.attributes["gns3.error_message"] == nil {
+		t.Errorf("Expected an error message attribute")
+	}
+	if len(s.errs) != 1 {
+		t.Errorf("Expected recorded errors: %v, got %v", 1, len(s.errs))
+	}
+}
+
+func TestTracingClientKeepsRequestContext(t *testing.T) {
+	tr := &testTracer{}
+	tc := gons3.TracingClient{Client: client, Tracer: tr}.WithContext(context.Background())
+
+	c := gons3.ProjectCreator{}
+	c.SetName("TestTracingClientKeepsRequestContext")
+	ci, err := gons3.CreateProject(tc, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	defer gons3.DeleteProject(client, ci.ProjectID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := gons3.WaitForProjectStatus(ctx, tc, ci.ProjectID, "closed")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, gons3.ErrStatusNotReached) {
+			t.Errorf("Expected error: %v, got %v", gons3.ErrStatusNotReached, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the request deadline to stop waiting")
+	}
+}
@@ -0,0 +1,122 @@
+package gns3tests
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"gons3/gons3test"
+	"testing"
+	"time"
+)
+
+func createWaitNode(t *testing.T, name string) gons3.Node {
+	t.Helper()
+	c := gons3.ProjectCreator{}
+	c.SetName(name)
+	ci, err := gons3.CreateProject(client, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	n := gons3.NodeCreator{}
+	n.SetName("PC1")
+	n.SetNodeType("vpcs")
+	n.SetComputeID("local")
+	node, err := gons3.CreateNode(client, ci.ProjectID, n)
+	if err != nil {
+		gons3.DeleteProject(client, ci.ProjectID)
+		t.Fatalf("Error creating node: %v", err)
+	}
+	return node
+}
+
+func setPollInterval(d time.Duration) func() {
+	interval := gons3.PollInterval
+	gons3.PollInterval = d
+	return func() { gons3.PollInterval = interval }
+}
+
+func TestWaitForNodeStatusNotification(t *testing.T) {
+	defer setPollInterval(time.Hour)()
+	node := createWaitNode(t, "TestWaitForNodeStatusNotification")
+	defer gons3.DeleteProject(client, node.ProjectID)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		gons3.StartNode(client, node.ProjectID, node.NodeID)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	n, err := gons3.WaitForNodeStatus(ctx, client, node.ProjectID, node.NodeID, "started")
+	if err != nil {
+		t.Fatalf("Error waiting for node: %v", err)
+	}
+	if !n.IsStarted() {
+		t.Errorf("Expected status: %v, got %v", "started", n.Status)
+	}
+	gons3.StopNode(client, node.ProjectID, node.NodeID)
+}
+
+func TestWaitForNodeStatusPolling(t *testing.T) {
+	defer setPollInterval(10 * time.Millisecond)()
+	node := createWaitNode(t, "TestWaitForNodeStatusPolling")
+	defer gons3.DeleteProject(client, node.ProjectID)
+
+	g := gons3test.NewFaultClient(client)
+	g.Inject(gons3test.StatusFault("GET", "/v2/projects/{id}/notifications", 404, "Not Found"))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		gons3.StartNode(client, node.ProjectID, node.NodeID)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := gons3.WaitForNodeStatus(ctx, g, node.ProjectID, node.NodeID, "started"); err != nil {
+		t.Fatalf("Error waiting for node: %v", err)
+	}
+	gons3.StopNode(client, node.ProjectID, node.NodeID)
+}
+
+func TestWaitForNodeStatusErrors(t *testing.T) {
+	node := createWaitNode(t, "TestWaitForNodeStatusErrors")
+	defer gons3.DeleteProject(client, node.ProjectID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := gons3.WaitForNodeStatus(ctx, client, node.ProjectID, node.NodeID, "started")
+	if !errors.Is(err, gons3.ErrStatusNotReached) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected errors: %v and %v, got %v", gons3.ErrStatusNotReached, context.DeadlineExceeded, err)
+	}
+
+	_, err = gons3.WaitForNodeStatus(context.Background(), client, node.ProjectID, "2ad6ab1a-0f0e-4b1a-9b1e-3f1a2b3c4d5e", "started")
+	if !gons3.IsNotFound(err) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrNotFound, err)
+	}
+	if _, err := gons3.WaitForNodeStatus(context.Background(), client, "", "", "started"); err != gons3.ErrEmptyID {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrEmptyID, err)
+	}
+}
+
+func TestWaitForProjectStatus(t *testing.T) {
+	defer setPollInterval(time.Hour)()
+	node := createWaitNode(t, "TestWaitForProjectStatus")
+	defer gons3.DeleteProject(client, node.ProjectID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := gons3.WaitForProjectStatus(ctx, client, node.ProjectID, "opened"); err != nil {
+		t.Fatalf("Error waiting for opened project: %v", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		gons3.CloseProject(client, node.ProjectID)
+	}()
+	proj, err := gons3.WaitForProjectStatus(ctx, client, node.ProjectID, "closed")
+	if err != nil {
+		t.Fatalf("Error waiting for closed project: %v", err)
+	}
+	if proj.IsOpened() {
+		t.Errorf("Expected status: %v, got %v", "closed", proj.Status)
+	}
+}
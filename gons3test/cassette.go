@@ -0,0 +1,412 @@
+package gons3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gons3"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrNoInteraction is returned by a Replayer when no recorded interaction matches the request.
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Cassette models the request and response pairs recorded from a GNS3 server.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction models a recorded request and response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest models a recorded request.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   Body   `json:"body,omitempty"`
+}
+
+// RecordedResponse models a recorded response.
+type RecordedResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        Body   `json:"body,omitempty"`
+	// Interrupted is set when the body was closed or failed before its end,
+	// such as a notification stream cancelled by its context. The replayed
+	// body then stays open after Body until the request's context is done.
+	Interrupted bool `json:"interrupted,omitempty"`
+}
+
+// Body is a recorded body. It is stored as text when possible, otherwise as base64.
+type Body []byte
+
+// MarshalJSON marshals the body as a string, or as an object holding base64 for binary data.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(struct {
+		Base64 []byte `json:"base64"`
+	}{b})
+}
+
+// UnmarshalJSON unmarshals a body marshaled by MarshalJSON.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	binary := struct {
+		Base64 []byte `json:"base64"`
+	}{}
+	if err := json.Unmarshal(data, &binary); err != nil {
+		return err
+	}
+	*b = binary.Base64
+	return nil
+}
+
+// LoadCassette loads a cassette from a JSON file.
+func LoadCassette(filename string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save saves the cassette to a JSON file.
+func (c *Cassette) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// Recorder is a GNS3Client that records every request sent by Client and its response.
+type Recorder struct {
+	Client gons3.GNS3Client
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a Recorder that wraps g.
+func NewRecorder(g gons3.GNS3Client) *Recorder {
+	return &Recorder{Client: g}
+}
+
+// GetSchemeAuthority gets the scheme and authority of the wrapped client.
+func (r *Recorder) GetSchemeAuthority() string {
+	return r.Client.GetSchemeAuthority()
+}
+
+// Do sends the request with the wrapped client and records the interaction.
+// The response body is passed through as it is read, so streams such as the
+// notification stream are not held back, and is recorded once it is read to
+// its end or closed.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Reserve the interaction so it keeps the order of the requests
+	r.mu.Lock()
+	i := len(r.cassette.Interactions)
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.RequestURI(),
+			Body:   reqBody,
+		},
+		Response: RecordedResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		},
+	})
+	r.mu.Unlock()
+
+	resp.Body = &recordingBody{ReadCloser: resp.Body, recorder: r, i: i}
+	return resp, nil
+}
+
+// recordingBody records a response body as it is read.
+type recordingBody struct {
+	io.ReadCloser
+	recorder *Recorder
+	i        int
+	buf      bytes.Buffer
+	once     sync.Once
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.record(false)
+	} else if err != nil {
+		b.record(true)
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.record(true)
+	return b.ReadCloser.Close()
+}
+
+func (b *recordingBody) record(interrupted bool) {
+	b.once.Do(func() {
+		b.recorder.mu.Lock()
+		defer b.recorder.mu.Unlock()
+		response := &b.recorder.cassette.Interactions[b.i].Response
+		response.Body = append(Body{}, b.buf.Bytes()...)
+		response.Interrupted = interrupted
+	})
+}
+
+// Cassette returns a copy of the recorded interactions.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction{}, r.cassette.Interactions...)}
+}
+
+// Save saves the recorded interactions to a JSON file.
+func (r *Recorder) Save(filename string) error {
+	return r.Cassette().Save(filename)
+}
+
+// Replayer is a GNS3Client that answers requests from a cassette.
+// Requests match the first unused interaction with the same method, path and
+// body. Ids are normalized, so a request may use a different id than the one
+// recorded as long as it is used consistently. Recorded ids in responses are
+// replaced by the ids the client used in their place.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	toRecorded   map[string]string
+	toLive       map[string]string
+}
+
+// NewReplayer creates a Replayer for the cassette.
+func NewReplayer(c *Cassette) *Replayer {
+	return &Replayer{
+		interactions: c.Interactions,
+		used:         make([]bool, len(c.Interactions)),
+		toRecorded:   map[string]string{},
+		toLive:       map[string]string{},
+	}
+}
+
+// LoadReplayer creates a Replayer for the cassette in a JSON file.
+func LoadReplayer(filename string) (*Replayer, error) {
+	c, err := LoadCassette(filename)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(c), nil
+}
+
+// GetSchemeAuthority gets a placeholder scheme and authority.
+func (r *Replayer) GetSchemeAuthority() string {
+	return "http://gns3.replay"
+}
+
+// Do answers the request with the matching recorded response.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := req.URL.RequestURI()
+	body := canonicalJSON(reqBody)
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Request.Method != req.Method {
+			continue
+		}
+		bindings := map[string]string{}
+		if !r.match(path, interaction.Request.Path, bindings) ||
+			!r.match(body, canonicalJSON(interaction.Request.Body), bindings) {
+			continue
+		}
+
+		r.used[i] = true
+		for live, recorded := range bindings {
+			r.bind(live, recorded)
+		}
+		return r.response(req, interaction.Response), nil
+	}
+	return nil, gons3.Wrap(ErrNoInteraction, fmt.Errorf("%v %v", req.Method, path))
+}
+
+// Unused returns the recorded interactions that were never replayed.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unused := []Interaction{}
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func (r *Replayer) bind(live, recorded string) {
+	r.toRecorded[live] = recorded
+	r.toLive[recorded] = live
+}
+
+// match compares live and recorded text with their ids normalized, adding
+// any newly paired ids to bindings.
+func (r *Replayer) match(live, recorded string, bindings map[string]string) bool {
+	liveIDs := idRegexp.FindAllStringIndex(live, -1)
+	recordedIDs := idRegexp.FindAllStringIndex(recorded, -1)
+	if len(liveIDs) != len(recordedIDs) {
+		return false
+	}
+	if idRegexp.ReplaceAllString(live, "{id}") != idRegexp.ReplaceAllString(recorded, "{id}") {
+		return false
+	}
+
+	for i := range liveIDs {
+		liveID := live[liveIDs[i][0]:liveIDs[i][1]]
+		recordedID := recorded[recordedIDs[i][0]:recordedIDs[i][1]]
+		if bound, ok := bindings[liveID]; ok {
+			if bound != recordedID {
+				return false
+			}
+			continue
+		}
+		if bound, ok := r.toRecorded[liveID]; ok {
+			if bound != recordedID {
+				return false
+			}
+			continue
+		}
+		if bound, ok := r.toLive[recordedID]; ok && bound != liveID {
+			return false
+		}
+		bindings[liveID] = recordedID
+	}
+	return true
+}
+
+func (r *Replayer) response(req *http.Request, recorded RecordedResponse) *http.Response {
+	body := idRegexp.ReplaceAllStringFunc(string(recorded.Body), func(recordedID string) string {
+		if live, ok := r.toLive[recordedID]; ok {
+			return live
+		}
+		r.bind(recordedID, recordedID)
+		return recordedID
+	})
+
+	header := http.Header{}
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+	var respBody io.ReadCloser = ioutil.NopCloser(bytes.NewReader([]byte(body)))
+	contentLength := int64(len(body))
+	if recorded.Interrupted {
+		respBody = newInterruptedBody(req, []byte(body))
+		contentLength = -1
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%v %v", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          respBody,
+		ContentLength: contentLength,
+		Request:       req,
+	}
+}
+
+// interruptedBody replays the body of an interrupted response, then blocks
+// until the request's context is done or the body is closed.
+type interruptedBody struct {
+	r      io.Reader
+	done   <-chan struct{}
+	err    func() error
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newInterruptedBody(req *http.Request, body []byte) *interruptedBody {
+	ctx := req.Context()
+	return &interruptedBody{
+		r:      bytes.NewReader(body),
+		done:   ctx.Done(),
+		err:    ctx.Err,
+		closed: make(chan struct{}),
+	}
+}
+
+func (b *interruptedBody) Read(p []byte) (int, error) {
+	if n, err := b.r.Read(p); err != io.EOF {
+		return n, err
+	}
+	select {
+	case <-b.done:
+		return 0, b.err()
+	case <-b.closed:
+		return 0, errors.New("read on closed response body")
+	}
+}
+
+func (b *interruptedBody) Close() error {
+	b.once.Do(func() { close(b.closed) })
+	return nil
+}
+
+var idRegexp = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// canonicalJSON sorts the keys of a JSON body so key order does not affect matching.
+func canonicalJSON(body []byte) string {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return string(body)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func readBody(body io.Reader) ([]byte, error) {
+	if body == nil {
+		return []byte{}, nil
+	}
+	return ioutil.ReadAll(body)
+}
@@ -0,0 +1,72 @@
+package gons3test
+
+import (
+	"errors"
+	"gons3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	recordedID := "11111111-1111-4111-8111-111111111111"
+	recorder := NewRecorder(s.Client())
+	c := gons3.ProjectCreator{}
+	c.SetName("TestRecordReplay")
+	c.SetProjectID(recordedID)
+	if _, err := gons3.CreateProject(recorder, c); err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+	if err := gons3.WriteProjectFile(recorder, recordedID, "data.bin", []byte{0xff, 0x00, 0xfe}); err != nil {
+		t.Fatalf("Error writing project file: %v", err)
+	}
+	if _, err := gons3.ReadProjectFile(recorder, recordedID, "data.bin"); err != nil {
+		t.Fatalf("Error reading project file: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "gons3test")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cassette.json")
+	if err := recorder.Save(filename); err != nil {
+		t.Fatalf("Error saving cassette: %v", err)
+	}
+
+	replayer, err := LoadReplayer(filename)
+	if err != nil {
+		t.Fatalf("Error loading cassette: %v", err)
+	}
+
+	liveID := "22222222-2222-4222-8222-222222222222"
+	c.SetProjectID(liveID)
+	proj, err := gons3.CreateProject(replayer, c)
+	if err != nil {
+		t.Fatalf("Error replaying create project: %v", err)
+	}
+	if proj.ProjectID != liveID {
+		t.Errorf("Expected project id: %v, got %v", liveID, proj.ProjectID)
+	}
+	if err := gons3.WriteProjectFile(replayer, liveID, "data.bin", []byte{0xff, 0x00, 0xfe}); err != nil {
+		t.Fatalf("Error replaying write project file: %v", err)
+	}
+	data, err := gons3.ReadProjectFile(replayer, liveID, "data.bin")
+	if err != nil {
+		t.Fatalf("Error replaying read project file: %v", err)
+	}
+	if string(data) != "\xff\x00\xfe" {
+		t.Errorf("Expected data: %v, got %v", []byte{0xff, 0x00, 0xfe}, data)
+	}
+
+	if _, err := gons3.GetProject(replayer, liveID); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("Expected unused interactions: %v, got %v", 0, len(unused))
+	}
+}
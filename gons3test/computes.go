@@ -0,0 +1,43 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/compute_handler.py
+
+package gons3test
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+)
+
+// builtinImages are the images of the local compute, keyed by emulator.
+var builtinImages = map[string][]string{
+	"dynamips": {"c7200-adventerprisek9-mz.124-24.T5.image"},
+	"qemu":     {"debian-12.qcow2"},
+}
+
+// serveComputes lists the images of the local compute, the only compute of
+// the fake controller.
+func (s *Server) serveComputes(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) != 3 || segments[2] != "images" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if segments[0] != "local" {
+		writeError(w, http.StatusNotFound, "Compute ID "+segments[0]+" doesn't exist")
+		return
+	}
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	images := []object{}
+	for _, filename := range builtinImages[segments[1]] {
+		images = append(images, object{
+			"filename": filename,
+			"path":     filename,
+			"md5sum":   fmt.Sprintf("%x", md5.Sum([]byte(filename))),
+			"filesize": 0,
+		})
+	}
+	writeJSON(w, http.StatusOK, images)
+}
@@ -0,0 +1,87 @@
+package gons3test
+
+import (
+	"net/http"
+)
+
+var drawingSchema = schema{
+	properties: map[string]string{
+		"drawing_id": "string",
+		"svg":        "string",
+		"x":          "integer",
+		"y":          "integer",
+		"z":          "integer",
+		"rotation":   "integer",
+		"locked":     "boolean",
+	},
+	strict: true,
+}
+
+func (s *Server) serveDrawings(w http.ResponseWriter, r *http.Request, projectID string, state *projectState, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, state.drawings.list())
+		case "POST":
+			body, ok := readValidJSON(w, r, drawingSchema)
+			if !ok {
+				return
+			}
+			drawingID, _ := body["drawing_id"].(string)
+			if drawingID == "" {
+				drawingID = newID()
+			}
+			drawing := object{
+				"drawing_id": drawingID,
+				"project_id": projectID,
+				"svg":        "<svg></svg>",
+				"x":          0,
+				"y":          0,
+				"z":          1,
+				"rotation":   0,
+				"locked":     false,
+			}
+			for k, v := range body {
+				drawing[k] = v
+			}
+			drawing = copyObject(drawing)
+			state.drawings.add(drawingID, drawing)
+			writeJSON(w, http.StatusCreated, drawing)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	drawingID := segments[0]
+	drawing, ok := state.drawings.get(drawingID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Drawing ID "+drawingID+" doesn't exist")
+		return
+	}
+	if len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, drawing)
+	case "PUT":
+		body, ok := readValidJSON(w, r, drawingSchema)
+		if !ok {
+			return
+		}
+		for k, v := range body {
+			if k != "drawing_id" {
+				drawing[k] = v
+			}
+		}
+		writeJSON(w, http.StatusCreated, drawing)
+	case "DELETE":
+		state.drawings.remove(drawingID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
@@ -0,0 +1,160 @@
+package gons3test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// topologyFile models the .gns3 file written to an exported archive.
+type topologyFile struct {
+	Name      string `json:"name"`
+	ProjectID string `json:"project_id"`
+	Revision  int    `json:"revision"`
+	Type      string `json:"type"`
+	Version   string `json:"version"`
+	Topology  struct {
+		Nodes    []object `json:"nodes"`
+		Links    []object `json:"links"`
+		Drawings []object `json:"drawings"`
+	} `json:"topology"`
+}
+
+func (s *Server) exportProject(w http.ResponseWriter, r *http.Request, proj object, state *projectState) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	t := topologyFile{Name: proj["name"].(string), ProjectID: proj["project_id"].(string), Revision: 9, Type: "topology", Version: "2.2.0"}
+	t.Topology.Nodes = state.nodes.list()
+	t.Topology.Links = state.links.list()
+	t.Topology.Drawings = state.drawings.list()
+	data, err := json.Marshal(t)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	files := map[string][]byte{proj["filename"].(string): data}
+	for name, data := range state.files {
+		if _, ok := files[name]; !ok {
+			files[name] = data
+		}
+	}
+	for name, data := range files {
+		f, err := z.Create(name)
+		if err == nil {
+			_, err = f.Write(data)
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if err := z.Close(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gns3project")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) importProject(w http.ResponseWriter, r *http.Request, projectID string) {
+	if r.Method != "POST" {
+		methodNotAllowed(w)
+		return
+	}
+	if _, ok := s.projects.get(projectID); ok {
+		writeError(w, http.StatusConflict, "Project ID "+projectID+" already exists")
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		writeError(w, http.StatusConflict, "Can't import project: "+err.Error())
+		return
+	}
+
+	state := newProjectState()
+	var t *topologyFile
+	for _, f := range z.File {
+		name, ok := cleanFilePath(strings.Split(f.Name, "/"))
+		if !ok {
+			writeError(w, http.StatusConflict, "Can't import project: invalid file "+f.Name)
+			return
+		}
+		rc, err := f.Open()
+		if err != nil {
+			writeError(w, http.StatusConflict, "Can't import project: "+err.Error())
+			return
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			writeError(w, http.StatusConflict, "Can't import project: "+err.Error())
+			return
+		}
+		if t == nil && path.Ext(name) == ".gns3" && !strings.Contains(name, "/") {
+			t = &topologyFile{}
+			if err := json.Unmarshal(content, t); err != nil {
+				writeError(w, http.StatusConflict, "Can't import project: "+err.Error())
+				return
+			}
+			continue
+		}
+		state.files[name] = content
+	}
+	if t == nil {
+		writeError(w, http.StatusConflict, "Can't import project: no .gns3 file found")
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = t.Name
+	}
+	for _, existing := range s.projects.list() {
+		if existing["name"] == name {
+			writeError(w, http.StatusConflict, "Project '"+name+"' already exists")
+			return
+		}
+	}
+
+	for _, node := range t.Topology.Nodes {
+		if node["console"] != nil {
+			node["console"] = s.nextConsole
+			s.nextConsole++
+		}
+		node["project_id"] = projectID
+		node["status"] = "stopped"
+		state.nodes.add(node["node_id"].(string), copyObject(node))
+	}
+	for _, link := range t.Topology.Links {
+		link["project_id"] = projectID
+		link["capturing"] = false
+		state.links.add(link["link_id"].(string), link)
+	}
+	for _, drawing := range t.Topology.Drawings {
+		drawing["project_id"] = projectID
+		state.drawings.add(drawing["drawing_id"].(string), drawing)
+	}
+
+	proj := copyObject(newProject(projectID, name))
+	s.projects.add(projectID, proj)
+	s.state[projectID] = state
+	writeJSON(w, http.StatusCreated, proj)
+}
@@ -0,0 +1,181 @@
+package gons3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gons3"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// FaultKind is the kind of failure injected by a FaultClient.
+type FaultKind int
+
+const (
+	// FaultTimeout fails the request with a timeout error without sending it.
+	FaultTimeout FaultKind = iota + 1
+	// FaultStatus answers the request with a GNS3 error without sending it.
+	FaultStatus
+	// FaultTruncatedBody sends the request and cuts the response body in half.
+	FaultTruncatedBody
+	// FaultNotJSON sends the request and replaces the response content type with text/html.
+	FaultNotJSON
+)
+
+// Fault models a failure injected into the requests that match Method and Endpoint.
+type Fault struct {
+	// Method is the request method to match, or "" for any method.
+	Method string
+	// Endpoint is the endpoint template to match, as returned by
+	// gons3.EndpointTemplate, or "" for any endpoint.
+	Endpoint string
+	Kind     FaultKind
+	// StatusCode and Message are the error returned by FaultStatus.
+	StatusCode int
+	Message    string
+	// Times is the number of requests to fail, or 0 to fail every request.
+	Times int
+}
+
+// TimeoutFault creates a fault that times out requests.
+func TimeoutFault(method, endpoint string) Fault {
+	return Fault{Method: method, Endpoint: endpoint, Kind: FaultTimeout}
+}
+
+// StatusFault creates a fault that answers requests with a GNS3 error.
+func StatusFault(method, endpoint string, statusCode int, message string) Fault {
+	return Fault{Method: method, Endpoint: endpoint, Kind: FaultStatus, StatusCode: statusCode, Message: message}
+}
+
+// TruncatedBodyFault creates a fault that truncates response bodies.
+func TruncatedBodyFault(method, endpoint string) Fault {
+	return Fault{Method: method, Endpoint: endpoint, Kind: FaultTruncatedBody}
+}
+
+// NotJSONFault creates a fault that replaces the response content type.
+func NotJSONFault(method, endpoint string) Fault {
+	return Fault{Method: method, Endpoint: endpoint, Kind: FaultNotJSON}
+}
+
+// FaultClient is a GNS3Client that injects scripted failures into the requests sent by Client.
+type FaultClient struct {
+	Client gons3.GNS3Client
+
+	mu     sync.Mutex
+	faults []*injectedFault
+}
+
+type injectedFault struct {
+	Fault
+	count int
+}
+
+// NewFaultClient creates a FaultClient that wraps g.
+func NewFaultClient(g gons3.GNS3Client) *FaultClient {
+	return &FaultClient{Client: g}
+}
+
+// Inject adds a fault. Faults are matched in the order they were injected.
+func (f *FaultClient) Inject(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &injectedFault{Fault: fault})
+}
+
+// Reset removes every fault.
+func (f *FaultClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = nil
+}
+
+// GetSchemeAuthority gets the scheme and authority of the wrapped client.
+func (f *FaultClient) GetSchemeAuthority() string {
+	return f.Client.GetSchemeAuthority()
+}
+
+// Do injects the first matching fault into the request, or sends it unchanged.
+func (f *FaultClient) Do(req *http.Request) (*http.Response, error) {
+	fault, ok := f.match(req)
+	if !ok {
+		return f.Client.Do(req)
+	}
+
+	switch fault.Kind {
+	case FaultTimeout:
+		return nil, &url.Error{Op: req.Method, URL: req.URL.String(), Err: timeoutError{}}
+	case FaultStatus:
+		body, _ := json.Marshal(map[string]interface{}{"message": fault.Message, "status": fault.StatusCode})
+		return &http.Response{
+			Status:        fmt.Sprintf("%v %v", fault.StatusCode, http.StatusText(fault.StatusCode)),
+			StatusCode:    fault.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	switch fault.Kind {
+	case FaultTruncatedBody:
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(io.MultiReader(
+			bytes.NewReader(body[:len(body)/2]),
+			errReader{io.ErrUnexpectedEOF},
+		))
+	case FaultNotJSON:
+		resp.Header.Set("Content-Type", "text/html")
+	}
+	return resp, nil
+}
+
+func (f *FaultClient) match(req *http.Request) (Fault, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	endpoint := gons3.EndpointTemplate(req.URL.Path)
+	for _, fault := range f.faults {
+		if fault.Method != "" && fault.Method != req.Method {
+			continue
+		}
+		if fault.Endpoint != "" && fault.Endpoint != endpoint {
+			continue
+		}
+		if fault.Times > 0 && fault.count >= fault.Times {
+			continue
+		}
+		fault.count++
+		return fault.Fault, true
+	}
+	return Fault{}, false
+}
+
+// timeoutError implements net.Error for a timed out request.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type errReader struct {
+	err error
+}
+
+func (e errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
@@ -0,0 +1,62 @@
+package gons3test
+
+import (
+	"errors"
+	"gons3"
+	"net"
+	"testing"
+)
+
+func TestFaultClient(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	fc := NewFaultClient(s.Client())
+	c := gons3.ProjectCreator{}
+	c.SetName("TestFaultClient")
+	proj, err := gons3.CreateProject(fc, c)
+	if err != nil {
+		t.Fatalf("Error creating project: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		fault Fault
+		is    error
+	}{
+		{"timeout", TimeoutFault("GET", "/v2/projects/{id}"), gons3.ErrRequestFailed},
+		{"conflict", StatusFault("GET", "/v2/projects/{id}", 409, "Project is locked"), gons3.ErrConflict},
+		{"server error", StatusFault("", "", 500, "Internal error"), gons3.ErrUnexpectedStatusCode},
+		{"truncated body", TruncatedBodyFault("GET", ""), gons3.ErrFailedToReadResult},
+		{"not json", NotJSONFault("", "/v2/projects/{id}"), gons3.ErrResponseNotJSON},
+	}
+	for _, tt := range tests {
+		fc.Reset()
+		fc.Inject(tt.fault)
+		if _, err := gons3.GetProject(fc, proj.ProjectID); !errors.Is(err, tt.is) {
+			t.Errorf("%v: Expected %v, got %v", tt.name, tt.is, err)
+		}
+	}
+
+	fc.Reset()
+	fc.Inject(TimeoutFault("", ""))
+	_, err = gons3.GetProject(fc, proj.ProjectID)
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected a timeout net.Error, got %v", err)
+	}
+
+	fc.Reset()
+	conflict := StatusFault("GET", "/v2/projects/{id}", 409, "Project is locked")
+	conflict.Times = 1
+	fc.Inject(conflict)
+	if _, err := gons3.GetProject(fc, proj.ProjectID); !gons3.IsConflict(err) {
+		t.Errorf("Expected IsConflict, got %v", err)
+	}
+	if _, err := gons3.GetProject(fc, proj.ProjectID); err != nil {
+		t.Errorf("Expected fault to be exhausted, got %v", err)
+	}
+	if _, err := gons3.GetProjects(fc); err != nil {
+		t.Errorf("Expected unmatched endpoint to succeed, got %v", err)
+	}
+}
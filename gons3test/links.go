@@ -0,0 +1,235 @@
+package gons3test
+
+import (
+	"fmt"
+	"net/http"
+)
+
+var linkSchema = schema{
+	properties: map[string]string{
+		"link_id":    "string",
+		"nodes":      "array",
+		"link_type":  "string",
+		"filters":    "object",
+		"suspend":    "boolean",
+		"link_style": "object",
+	},
+	required: []string{"nodes"},
+	enums:    map[string][]string{"link_type": {"ethernet", "serial"}},
+	strict:   true,
+}
+
+var linkUpdateSchema = schema{
+	properties: linkSchema.properties,
+	enums:      linkSchema.enums,
+	strict:     true,
+}
+
+var linkNodeSchema = schema{
+	properties: map[string]string{
+		"node_id":        "string",
+		"adapter_number": "integer",
+		"port_number":    "integer",
+		"label":          "object",
+	},
+	required: []string{"node_id", "adapter_number", "port_number"},
+	strict:   true,
+}
+
+var captureSchema = schema{
+	properties: map[string]string{
+		"capture_file_name": "string",
+		"data_link_type":    "string",
+	},
+	strict: true,
+}
+
+func (s *Server) serveLinks(w http.ResponseWriter, r *http.Request, projectID string, state *projectState, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, state.links.list())
+		case "POST":
+			s.createLink(w, r, projectID, state)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	linkID := segments[0]
+	link, ok := state.links.get(linkID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Link ID "+linkID+" doesn't exist")
+		return
+	}
+
+	if len(segments) == 1 {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, link)
+		case "PUT":
+			body, ok := readValidJSON(w, r, linkUpdateSchema)
+			if !ok {
+				return
+			}
+			if nodes, ok := body["nodes"]; ok {
+				if !validLinkNodes(w, state, nodes, linkID) {
+					return
+				}
+			}
+			for k, v := range body {
+				link[k] = v
+			}
+			writeJSON(w, http.StatusCreated, link)
+		case "DELETE":
+			state.links.remove(linkID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	switch segments[1] {
+	case "start_capture":
+		body, ok := readValidJSON(w, r, captureSchema)
+		if !ok {
+			return
+		}
+		if link["capturing"] == true {
+			writeError(w, http.StatusConflict, "Packet capture is already activated on this link")
+			return
+		}
+		name, _ := body["capture_file_name"].(string)
+		if name == "" {
+			name = linkID + ".pcap"
+		}
+		link["capturing"] = true
+		link["capture_file_name"] = name
+		link["capture_file_path"] = "/opt/gns3/projects/" + projectID + "/project-files/captures/" + name
+		link["capture_compute_id"] = "local"
+		writeJSON(w, http.StatusCreated, link)
+	case "stop_capture":
+		link["capturing"] = false
+		link["capture_file_name"] = nil
+		link["capture_file_path"] = nil
+		link["capture_compute_id"] = nil
+		writeJSON(w, http.StatusCreated, link)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) createLink(w http.ResponseWriter, r *http.Request, projectID string, state *projectState) {
+	body, ok := readValidJSON(w, r, linkSchema)
+	if !ok {
+		return
+	}
+	if !validLinkNodes(w, state, body["nodes"], "") {
+		return
+	}
+
+	linkID, _ := body["link_id"].(string)
+	if linkID == "" {
+		linkID = newID()
+	}
+	link := object{
+		"link_id":            linkID,
+		"project_id":         projectID,
+		"nodes":              body["nodes"],
+		"link_type":          "ethernet",
+		"capturing":          false,
+		"capture_file_name":  nil,
+		"capture_file_path":  nil,
+		"capture_compute_id": nil,
+		"filters":            object{},
+		"suspend":            false,
+		"link_style":         object{},
+	}
+	for k, v := range body {
+		link[k] = v
+	}
+	link = copyObject(link)
+	state.links.add(linkID, link)
+	writeJSON(w, http.StatusCreated, link)
+}
+
+// validLinkNodes validates the link endpoints, writing the error response on failure.
+func validLinkNodes(w http.ResponseWriter, state *projectState, v interface{}, linkID string) bool {
+	nodes, _ := v.([]interface{})
+	if len(nodes) != 2 {
+		validator, size := "minItems", "short"
+		if len(nodes) > 2 {
+			validator, size = "maxItems", "long"
+		}
+		writeError(w, http.StatusBadRequest, linkSchema.errorf(
+			map[string]interface{}{"type": "array", validator: 2}, "%v is too %v", pyRepr(nodes), size))
+		return false
+	}
+
+	for i, n := range nodes {
+		end, ok := n.(map[string]interface{})
+		if !ok {
+			writeError(w, http.StatusBadRequest, linkSchema.errorf(
+				map[string]interface{}{"type": "object"}, "%v is not of type 'object'", pyRepr(n)))
+			return false
+		}
+		if msg := linkNodeSchema.validate(end); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return false
+		}
+
+		nodeID := end["node_id"].(string)
+		node, ok := state.nodes.get(nodeID)
+		if !ok {
+			writeError(w, http.StatusNotFound, "Node ID "+nodeID+" doesn't exist")
+			return false
+		}
+		adapter, port := end["adapter_number"].(float64), end["port_number"].(float64)
+		if _, ok := nodePort(node, adapter, port); !ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Port %v/%v doesn't exist on %v", adapter, port, node["name"]))
+			return false
+		}
+		if i == 1 && nodes[0].(map[string]interface{})["node_id"] == nodeID {
+			writeError(w, http.StatusConflict, "Cannot connect to itself")
+			return false
+		}
+		for _, link := range state.links.list() {
+			if link["link_id"] != linkID && linkHasPort(link, nodeID, adapter, port) {
+				writeError(w, http.StatusConflict, fmt.Sprintf("Port %v/%v is already used", adapter, port))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func linkEnds(link object) []map[string]interface{} {
+	ends := []map[string]interface{}{}
+	nodes, _ := link["nodes"].([]interface{})
+	for _, n := range nodes {
+		if end, ok := n.(map[string]interface{}); ok {
+			ends = append(ends, end)
+		}
+	}
+	return ends
+}
+
+func linkHasNode(link object, nodeID string) bool {
+	for _, end := range linkEnds(link) {
+		if end["node_id"] == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+func linkHasPort(link object, nodeID string, adapter, port float64) bool {
+	for _, end := range linkEnds(link) {
+		if end["node_id"] == nodeID && end["adapter_number"] == adapter && end["port_number"] == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,486 @@
+package gons3test
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+var nodeTypes = []string{
+	"cloud", "nat", "ethernet_hub", "ethernet_switch", "frame_relay_switch", "atm_switch",
+	"docker", "dynamips", "vpcs", "traceng", "virtualbox", "vmware", "iou", "qemu",
+}
+
+var nodeSchema = schema{
+	properties: map[string]string{
+		"name":               "string",
+		"node_id":            "string",
+		"node_type":          "string",
+		"compute_id":         "string",
+		"console":            "integer|null",
+		"console_type":       "string",
+		"console_auto_start": "boolean",
+		"properties":         "object",
+		"symbol":             "string|null",
+		"label":              "object",
+		"locked":             "boolean",
+		"x":                  "integer",
+		"y":                  "integer",
+		"z":                  "integer",
+		"port_name_format":   "string",
+		"port_segment_size":  "integer",
+		"first_port_name":    "string|null",
+		"custom_adapters":    "array",
+	},
+	required: []string{"name", "node_type", "compute_id"},
+	enums:    map[string][]string{"node_type": nodeTypes},
+	strict:   true,
+}
+
+var nodeUpdateSchema = schema{
+	properties: nodeSchema.properties,
+	enums:      nodeSchema.enums,
+	strict:     true,
+}
+
+var nodeDuplicateSchema = schema{
+	properties: map[string]string{
+		"x": "integer",
+		"y": "integer",
+		"z": "integer",
+	},
+	required: []string{"x", "y"},
+	strict:   true,
+}
+
+func (s *Server) serveNodes(w http.ResponseWriter, r *http.Request, projectID string, state *projectState, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, state.nodes.list())
+		case "POST":
+			s.createNode(w, r, projectID, state)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	// Actions on every node of the project
+	if status, ok := nodeActions[segments[0]]; ok && len(segments) == 1 {
+		if r.Method != "POST" {
+			methodNotAllowed(w)
+			return
+		}
+		for _, node := range state.nodes.list() {
+			node["status"] = status
+			s.notify(projectID, "node.updated", node)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	nodeID := segments[0]
+	node, ok := state.nodes.get(nodeID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Node ID "+nodeID+" doesn't exist")
+		return
+	}
+
+	if len(segments) == 1 {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, node)
+		case "PUT":
+			s.updateNode(w, r, projectID, node)
+		case "DELETE":
+			for _, link := range state.links.list() {
+				if linkHasNode(link, nodeID) {
+					state.links.remove(link["link_id"].(string))
+				}
+			}
+			state.nodes.remove(nodeID)
+			s.notify(projectID, "node.deleted", node)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	if status, ok := nodeActions[segments[1]]; ok && len(segments) == 2 {
+		if r.Method != "POST" {
+			methodNotAllowed(w)
+			return
+		}
+		node["status"] = status
+		s.notify(projectID, "node.updated", node)
+		writeJSON(w, http.StatusOK, node)
+		return
+	}
+
+	switch segments[1] {
+	case "duplicate":
+		if r.Method != "POST" {
+			methodNotAllowed(w)
+			return
+		}
+		s.duplicateNode(w, r, projectID, state, node)
+	case "isolate", "unisolate":
+		if r.Method != "POST" {
+			methodNotAllowed(w)
+			return
+		}
+		for _, link := range state.links.list() {
+			if linkHasNode(link, nodeID) {
+				link["suspend"] = segments[1] == "isolate"
+				s.notify(projectID, "link.updated", link)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case "dynamips":
+		serveIdlePC(w, r, node, segments[2:])
+	case "qemu":
+		serveQemuDiskImage(w, r, state, node, segments[2:])
+	case "files":
+		s.serveNodeFile(w, r, state, node, segments[2:])
+	case "links":
+		links := []object{}
+		for _, link := range state.links.list() {
+			if linkHasNode(link, nodeID) {
+				links = append(links, link)
+			}
+		}
+		writeJSON(w, http.StatusOK, links)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// idlePCProposals are the Idle-PC values proposed for every Dynamips router.
+var idlePCProposals = []string{"0x60606f54", "0x60608040", "0x6060a0c8", "0x6060a5f8", "0x6060b270"}
+
+// serveIdlePC serves the Idle-PC computations of a Dynamips router.
+func serveIdlePC(w http.ResponseWriter, r *http.Request, node object, segments []string) {
+	if node["node_type"] != "dynamips" || len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+	switch segments[0] {
+	case "idlepc_proposals":
+		writeJSON(w, http.StatusOK, idlePCProposals)
+	case "auto_idlepc":
+		writeJSON(w, http.StatusOK, object{"idlepc": idlePCProposals[0]})
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+var qemuDiskImageSchema = schema{
+	properties: map[string]string{
+		"format":         "string",
+		"size":           "integer",
+		"preallocation":  "string",
+		"cluster_size":   "integer",
+		"refcount_bits":  "integer",
+		"lazy_refcounts": "string",
+		"subformat":      "string",
+		"static":         "string",
+		"zeroed_grain":   "string",
+		"adapter_type":   "string",
+	},
+	required: []string{"format", "size"},
+	enums:    map[string][]string{"format": {"qcow2", "qcow", "vpc", "vdi", "vmdk", "raw"}},
+	strict:   true,
+}
+
+var qemuDiskImageUpdateSchema = schema{
+	properties: map[string]string{"extend": "integer"},
+	strict:     true,
+}
+
+// serveQemuDiskImage creates and extends the disk images of a qemu node. The
+// images are files in the node's directory that describe the disk instead of
+// holding its data.
+func serveQemuDiskImage(w http.ResponseWriter, r *http.Request, state *projectState, node object, segments []string) {
+	if node["node_type"] != "qemu" || len(segments) != 2 || segments[0] != "disk_image" || segments[1] == "" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	name := path.Join("project-files", "qemu", node["node_id"].(string), segments[1])
+	data, exists := state.files[name]
+
+	switch r.Method {
+	case "POST":
+		body, ok := readValidJSON(w, r, qemuDiskImageSchema)
+		if !ok {
+			return
+		}
+		if exists {
+			writeError(w, http.StatusConflict, "Could not create disk image "+segments[1]+" as it already exists")
+			return
+		}
+		state.files[name] = []byte(fmt.Sprintf("format: %v\nsize: %v\n", body["format"], body["size"]))
+		w.WriteHeader(http.StatusCreated)
+	case "PUT":
+		body, ok := readValidJSON(w, r, qemuDiskImageUpdateSchema)
+		if !ok {
+			return
+		}
+		if !exists {
+			writeError(w, http.StatusNotFound, "Disk image "+segments[1]+" doesn't exist")
+			return
+		}
+		var format string
+		var size float64
+		if _, err := fmt.Sscanf(string(data), "format: %s\nsize: %g\n", &format, &size); err != nil {
+			writeError(w, http.StatusConflict, "Could not update disk image "+segments[1]+": "+err.Error())
+			return
+		}
+		extend, _ := body["extend"].(float64)
+		state.files[name] = []byte(fmt.Sprintf("format: %v\nsize: %v\n", format, size+extend))
+		w.WriteHeader(http.StatusCreated)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// nodeActions maps the node action endpoints to the resulting node status.
+var nodeActions = map[string]string{
+	"start":   "started",
+	"stop":    "stopped",
+	"suspend": "suspended",
+	"reload":  "started",
+}
+
+func (s *Server) createNode(w http.ResponseWriter, r *http.Request, projectID string, state *projectState) {
+	body, ok := readValidJSON(w, r, nodeSchema)
+	if !ok {
+		return
+	}
+
+	nodeID, _ := body["node_id"].(string)
+	if nodeID == "" {
+		nodeID = newID()
+	}
+	if _, ok := state.nodes.get(nodeID); ok {
+		writeError(w, http.StatusConflict, "Node ID "+nodeID+" already exists")
+		return
+	}
+
+	node := s.newNode(projectID, nodeID, body)
+	state.nodes.add(nodeID, node)
+	s.notify(projectID, "node.created", node)
+	writeJSON(w, http.StatusCreated, node)
+}
+
+// duplicateNode copies a stopped node and its files. The copy is named after
+// the original like on a GNS3 server, so R1 is duplicated as R2.
+func (s *Server) duplicateNode(w http.ResponseWriter, r *http.Request, projectID string, state *projectState, node object) {
+	body, ok := readValidJSON(w, r, nodeDuplicateSchema)
+	if !ok {
+		return
+	}
+	if node["status"] != "stopped" {
+		writeError(w, http.StatusConflict, "Cannot duplicate node data while the node is running")
+		return
+	}
+
+	nodeID := newID()
+	duplicate := copyObject(node)
+	duplicate["node_id"] = nodeID
+	duplicate["name"] = allocateNodeName(state, node["name"].(string))
+	duplicate["locked"] = false
+	duplicate["x"] = body["x"]
+	duplicate["y"] = body["y"]
+	duplicate["z"] = node["z"]
+	if z, ok := body["z"]; ok {
+		duplicate["z"] = z
+	}
+	if label, ok := duplicate["label"].(map[string]interface{}); ok {
+		label["text"] = duplicate["name"]
+	}
+	if console, ok := duplicate["console"].(float64); ok && console > 0 {
+		duplicate["console"] = s.nextConsole
+		s.nextConsole++
+	}
+
+	dir := path.Join("project-files", node["node_type"].(string), node["node_id"].(string)) + "/"
+	for name, data := range state.files {
+		if strings.HasPrefix(name, dir) {
+			name = path.Join("project-files", node["node_type"].(string), nodeID, strings.TrimPrefix(name, dir))
+			state.files[name] = append([]byte{}, data...)
+		}
+	}
+
+	state.nodes.add(nodeID, duplicate)
+	s.notify(projectID, "node.created", duplicate)
+	writeJSON(w, http.StatusCreated, duplicate)
+}
+
+// allocateNodeName finds a free node name from name by replacing its trailing
+// number, like the GNS3 controller.
+func allocateNodeName(state *projectState, name string) string {
+	used := map[interface{}]bool{}
+	for _, node := range state.nodes.list() {
+		used[node["name"]] = true
+	}
+	if !used[name] {
+		return name
+	}
+	base := strings.TrimRight(name, "0123456789")
+	for i := 1; ; i++ {
+		if candidate := base + fmt.Sprint(i); !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// newNode creates a node with the defaults of a GNS3 server, overridden by body.
+func (s *Server) newNode(projectID, nodeID string, body object) object {
+	node := object{
+		"node_id":            nodeID,
+		"project_id":         projectID,
+		"name":               body["name"],
+		"node_type":          body["node_type"],
+		"compute_id":         body["compute_id"],
+		"status":             "stopped",
+		"console":            nil,
+		"console_host":       "127.0.0.1",
+		"console_type":       "none",
+		"console_auto_start": false,
+		"command_line":       "",
+		"node_directory":     nil,
+		"properties":         object{},
+		"symbol":             ":/symbols/computer.svg",
+		"label":              object{"text": body["name"], "x": 0, "y": -25, "rotation": 0, "style": ""},
+		"locked":             false,
+		"x":                  0,
+		"y":                  0,
+		"z":                  1,
+		"port_name_format":   "Ethernet{0}",
+		"port_segment_size":  0,
+		"first_port_name":    nil,
+		"custom_adapters":    []interface{}{},
+	}
+	switch body["node_type"] {
+	case "ethernet_hub", "ethernet_switch", "cloud", "nat", "frame_relay_switch", "atm_switch":
+	default:
+		node["console"] = s.nextConsole
+		node["console_type"] = "telnet"
+		s.nextConsole++
+	}
+	for k, v := range body {
+		node[k] = v
+	}
+	node = copyObject(node)
+	node["ports"] = nodePorts(node)
+	return copyObject(node)
+}
+
+func (s *Server) updateNode(w http.ResponseWriter, r *http.Request, projectID string, node object) {
+	body, ok := readValidJSON(w, r, nodeUpdateSchema)
+	if !ok {
+		return
+	}
+
+	for k, v := range body {
+		if k == "properties" {
+			properties, _ := node["properties"].(map[string]interface{})
+			if properties == nil {
+				properties = map[string]interface{}{}
+			}
+			for pk, pv := range v.(map[string]interface{}) {
+				properties[pk] = pv
+			}
+			node["properties"] = properties
+			continue
+		}
+		node[k] = v
+	}
+	if name, ok := body["name"]; ok {
+		if label, ok := node["label"].(map[string]interface{}); ok {
+			label["text"] = name
+		}
+	}
+	node["ports"] = copyObject(object{"ports": nodePorts(node)})["ports"]
+	s.notify(projectID, "node.updated", node)
+	writeJSON(w, http.StatusOK, node)
+}
+
+// nodePorts generates the ports of a node from its type and adapters.
+func nodePorts(node object) []object {
+	properties, _ := node["properties"].(map[string]interface{})
+	adapters := 1
+	if a, ok := properties["adapters"].(float64); ok {
+		adapters = int(a)
+	}
+	format, _ := node["port_name_format"].(string)
+	if format == "" || !strings.Contains(format, "{0}") {
+		format = "Ethernet{0}"
+	}
+
+	port := func(name, shortName string, adapter, number int) object {
+		return object{
+			"name":            name,
+			"short_name":      shortName,
+			"adapter_number":  adapter,
+			"port_number":     number,
+			"link_type":       "ethernet",
+			"data_link_types": object{"Ethernet": "DLT_EN10MB"},
+		}
+	}
+
+	ports := []object{}
+	switch node["node_type"] {
+	case "ethernet_switch", "ethernet_hub":
+		for i := 0; i < 8; i++ {
+			ports = append(ports, port(fmt.Sprintf("Ethernet%v", i), fmt.Sprintf("e%v", i), 0, i))
+		}
+	case "iou":
+		ethernetAdapters := 2
+		if a, ok := properties["ethernet_adapters"].(float64); ok {
+			ethernetAdapters = int(a)
+		}
+		for a := 0; a < ethernetAdapters; a++ {
+			for p := 0; p < 4; p++ {
+				ports = append(ports, port(fmt.Sprintf("Ethernet%v/%v", a, p), fmt.Sprintf("e%v/%v", a, p), a, p))
+			}
+		}
+	case "dynamips":
+		ports = append(ports, port("FastEthernet0/0", "f0/0", 0, 0))
+	case "nat":
+		ports = append(ports, port("nat0", "nat0", 0, 0))
+	case "cloud":
+		ports = append(ports, port("eth0", "eth0", 0, 0))
+	case "docker":
+		for i := 0; i < adapters; i++ {
+			ports = append(ports, port(fmt.Sprintf("eth%v", i), fmt.Sprintf("eth%v", i), i, 0))
+		}
+	case "vpcs", "traceng":
+		ports = append(ports, port("Ethernet0", "e0", 0, 0))
+	default:
+		for i := 0; i < adapters; i++ {
+			name := strings.Replace(format, "{0}", fmt.Sprint(i), -1)
+			ports = append(ports, port(name, fmt.Sprintf("e%v", i), i, 0))
+		}
+	}
+	return ports
+}
+
+// nodePort finds the port of a node by adapter and port number.
+func nodePort(node object, adapter, number float64) (map[string]interface{}, bool) {
+	ports, _ := node["ports"].([]interface{})
+	for _, p := range ports {
+		port, _ := p.(map[string]interface{})
+		if port["adapter_number"] == adapter && port["port_number"] == number {
+			return port, true
+		}
+	}
+	return nil, false
+}
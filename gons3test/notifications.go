@@ -0,0 +1,100 @@
+package gons3test
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// notification is a message of the project notification stream.
+type notification struct {
+	Action string      `json:"action"`
+	Event  interface{} `json:"event"`
+}
+
+// notify sends a notification to the subscribers of the project stream.
+// Slow subscribers miss notifications rather than block the server.
+func (s *Server) notify(projectID, action string, event object) {
+	n := notification{Action: action, Event: copyObject(event)}
+	for ch := range s.subscribers[projectID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
+
+// serveNotifications streams the notifications of a project as JSON lines
+// until the client disconnects or the project is deleted.
+func (s *Server) serveNotifications(w http.ResponseWriter, r *http.Request, projectID string) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.projects.get(projectID); !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "Project ID "+projectID+" doesn't exist")
+		return
+	}
+	ch := make(chan notification, 64)
+	if s.subscribers[projectID] == nil {
+		s.subscribers[projectID] = map[chan notification]bool{}
+	}
+	s.subscribers[projectID][ch] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers[projectID], ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	e := json.NewEncoder(w)
+	write := func(n notification) bool {
+		if err := e.Encode(n); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+
+	if !write(notification{Action: "ping", Event: object{"cpu_usage_percent": 0, "memory_usage_percent": 0}}) {
+		return
+	}
+	for {
+		select {
+		case n := <-ch:
+			if !write(n) || n.Action == "project.deleted" {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+// SetNodeStatus changes the status of a node, as when a node crashes or
+// finishes booting, and notifies the project stream.
+func (s *Server) SetNodeStatus(projectID, nodeID, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.state[projectID]
+	if !ok {
+		return false
+	}
+	node, ok := state.nodes.get(nodeID)
+	if !ok {
+		return false
+	}
+	node["status"] = status
+	s.notify(projectID, "node.updated", node)
+	return true
+}
@@ -0,0 +1,298 @@
+package gons3test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var projectSchema = schema{
+	properties: map[string]string{
+		"name":                  "string",
+		"project_id":            "string|null",
+		"path":                  "string",
+		"auto_close":            "boolean",
+		"auto_open":             "boolean",
+		"auto_start":            "boolean",
+		"scene_height":          "integer",
+		"scene_width":           "integer",
+		"zoom":                  "integer",
+		"show_layers":           "boolean",
+		"snap_to_grid":          "boolean",
+		"show_grid":             "boolean",
+		"grid_size":             "integer",
+		"drawing_grid_size":     "integer",
+		"show_interface_labels": "boolean",
+		"supplier":              "object|null",
+		"variables":             "array|null",
+	},
+	required: []string{"name"},
+	strict:   true,
+}
+
+var projectUpdateSchema = schema{
+	properties: projectSchema.properties,
+	strict:     true,
+}
+
+// projectState holds the resources that belong to a project.
+type projectState struct {
+	files     map[string][]byte
+	nodes     *collection
+	links     *collection
+	drawings  *collection
+	snapshots *collection
+	saved     map[string]*projectState
+}
+
+func newProjectState() *projectState {
+	return &projectState{
+		files:     map[string][]byte{},
+		nodes:     newCollection(),
+		links:     newCollection(),
+		drawings:  newCollection(),
+		snapshots: newCollection(),
+		saved:     map[string]*projectState{},
+	}
+}
+
+// copy copies the resources captured by a snapshot.
+func (p *projectState) copy() *projectState {
+	c := newProjectState()
+	for name, data := range p.files {
+		c.files[name] = append([]byte{}, data...)
+	}
+	c.nodes = p.nodes.copy()
+	c.links = p.links.copy()
+	c.drawings = p.drawings.copy()
+	return c
+}
+
+func (s *Server) serveProjects(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, s.projects.list())
+		case "POST":
+			s.createProject(w, r)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	projectID := segments[0]
+	if len(segments) == 2 && segments[1] == "import" {
+		s.importProject(w, r, projectID)
+		return
+	}
+	proj, ok := s.projects.get(projectID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Project ID "+projectID+" doesn't exist")
+		return
+	}
+	state := s.state[projectID]
+
+	if len(segments) == 1 {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, proj)
+		case "PUT":
+			s.updateProject(w, r, proj)
+		case "DELETE":
+			s.projects.remove(projectID)
+			delete(s.state, projectID)
+			s.notify(projectID, "project.deleted", proj)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	switch segments[1] {
+	case "open", "close":
+		if r.Method != "POST" || len(segments) != 2 {
+			methodNotAllowed(w)
+			return
+		}
+		proj["status"] = map[string]string{"open": "opened", "close": "closed"}[segments[1]]
+		s.notify(projectID, "project."+proj["status"].(string), proj)
+		writeJSON(w, http.StatusCreated, proj)
+	case "export":
+		s.exportProject(w, r, proj, state)
+	case "files":
+		s.serveProjectFile(w, r, state, segments[2:])
+	case "nodes":
+		s.serveNodes(w, r, projectID, state, segments[2:])
+	case "links":
+		s.serveLinks(w, r, projectID, state, segments[2:])
+	case "drawings":
+		s.serveDrawings(w, r, projectID, state, segments[2:])
+	case "templates":
+		s.serveTemplateUsage(w, r, projectID, state, segments[2:])
+	case "snapshots":
+		s.serveSnapshots(w, r, proj, state, segments[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	body, ok := readValidJSON(w, r, projectSchema)
+	if !ok {
+		return
+	}
+
+	name := body["name"].(string)
+	for _, existing := range s.projects.list() {
+		if existing["name"] == name {
+			writeError(w, http.StatusConflict, "Project '"+name+"' already exists")
+			return
+		}
+	}
+
+	projectID, _ := body["project_id"].(string)
+	if projectID == "" {
+		projectID = newID()
+	}
+	if _, ok := s.projects.get(projectID); ok {
+		writeError(w, http.StatusConflict, "Project ID "+projectID+" already exists")
+		return
+	}
+
+	proj := newProject(projectID, name)
+	for k, v := range body {
+		if k != "project_id" {
+			proj[k] = v
+		}
+	}
+	proj = copyObject(proj)
+
+	s.projects.add(projectID, proj)
+	s.state[projectID] = newProjectState()
+	writeJSON(w, http.StatusCreated, proj)
+}
+
+// newProject creates a project with the defaults of a GNS3 server.
+func newProject(projectID, name string) object {
+	return object{
+		"name":                  name,
+		"project_id":            projectID,
+		"path":                  "/opt/gns3/projects/" + projectID,
+		"filename":              name + ".gns3",
+		"status":                "opened",
+		"auto_close":            true,
+		"auto_open":             false,
+		"auto_start":            false,
+		"scene_height":          1000,
+		"scene_width":           2000,
+		"zoom":                  100,
+		"show_layers":           false,
+		"snap_to_grid":          false,
+		"show_grid":             false,
+		"grid_size":             75,
+		"drawing_grid_size":     25,
+		"show_interface_labels": false,
+		"supplier":              nil,
+		"variables":             nil,
+	}
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, proj object) {
+	body, ok := readValidJSON(w, r, projectUpdateSchema)
+	if !ok {
+		return
+	}
+
+	if name, ok := body["name"].(string); ok && name != proj["name"] {
+		for _, existing := range s.projects.list() {
+			if existing["name"] == name {
+				writeError(w, http.StatusConflict, "Project '"+name+"' already exists")
+				return
+			}
+		}
+		proj["filename"] = name + ".gns3"
+	}
+	for k, v := range body {
+		proj[k] = v
+	}
+	s.notify(proj["project_id"].(string), "project.updated", proj)
+	writeJSON(w, http.StatusOK, proj)
+}
+
+func (s *Server) serveProjectFile(w http.ResponseWriter, r *http.Request, state *projectState, segments []string) {
+	name, ok := cleanFilePath(segments)
+	if !ok {
+		writeError(w, http.StatusForbidden, "Permission denied")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		data, ok := state.files[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, "File "+name+" doesn't exist")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	case "POST":
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		state.files[name] = data
+		w.WriteHeader(http.StatusOK)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// serveNodeFile serves the files of a node, which are kept in the node's
+// directory of the project like on a GNS3 server.
+func (s *Server) serveNodeFile(w http.ResponseWriter, r *http.Request, state *projectState, node object, segments []string) {
+	name, ok := cleanFilePath(segments)
+	if !ok {
+		writeError(w, http.StatusForbidden, "Permission denied")
+		return
+	}
+	name = path.Join("project-files", node["node_type"].(string), node["node_id"].(string), name)
+
+	switch r.Method {
+	case "GET":
+		data, ok := state.files[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, "File "+name+" doesn't exist")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	case "POST":
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		state.files[name] = data
+		w.WriteHeader(http.StatusCreated)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// cleanFilePath joins the file path segments, rejecting paths outside of the directory.
+func cleanFilePath(segments []string) (string, bool) {
+	name := path.Clean(strings.Join(segments, "/"))
+	if name == "." || name == ".." || strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+		return "", false
+	}
+	return name, true
+}
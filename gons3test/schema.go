@@ -0,0 +1,160 @@
+package gons3test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// schema is a small subset of the jsonschema validation done by GNS3.
+type schema struct {
+	// properties maps property names to their types, such as "integer" or "object|null".
+	properties map[string]string
+	required   []string
+	enums      map[string][]string
+	// strict rejects properties that are not listed.
+	strict bool
+}
+
+// validate returns the GNS3 error message of the first validation failure, or "".
+func (s schema) validate(o object) string {
+	for _, name := range s.required {
+		if _, ok := o[name]; !ok {
+			return s.errorf(s.json(), "'%v' is a required property", name)
+		}
+	}
+
+	names := make([]string, 0, len(o))
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if s.strict {
+		for _, name := range names {
+			if _, ok := s.properties[name]; !ok {
+				return s.errorf(s.json(), "Additional properties are not allowed ('%v' was unexpected)", name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		types, ok := s.properties[name]
+		if !ok {
+			continue
+		}
+		if !hasType(o[name], types) {
+			propSchema := map[string]interface{}{"type": strings.Split(types, "|")}
+			return s.errorf(propSchema, "%v is not of type %v", pyRepr(o[name]), pyTypes(types))
+		}
+		if enum, ok := s.enums[name]; ok && !contains(enum, o[name]) {
+			propSchema := map[string]interface{}{"enum": enum}
+			return s.errorf(propSchema, "%v is not one of %v", pyRepr(o[name]), pyRepr(enum))
+		}
+	}
+	return ""
+}
+
+func (s schema) json() map[string]interface{} {
+	properties := map[string]interface{}{}
+	for name, types := range s.properties {
+		properties[name] = map[string]interface{}{"type": strings.Split(types, "|")}
+	}
+	j := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": !s.strict,
+	}
+	if len(s.required) > 0 {
+		j["required"] = s.required
+	}
+	return j
+}
+
+func (s schema) errorf(failing map[string]interface{}, format string, args ...interface{}) string {
+	data, _ := json.Marshal(failing)
+	return fmt.Sprintf("Invalid JSON: %v in schema: %s", fmt.Sprintf(format, args...), data)
+}
+
+func hasType(v interface{}, types string) bool {
+	for _, t := range strings.Split(types, "|") {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := v.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := v.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(values []string, v interface{}) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// pyRepr formats v the way Python's repr would, as found in jsonschema messages.
+func pyRepr(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case string:
+		return "'" + v + "'"
+	case float64:
+		if v == math.Trunc(v) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%v", v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = pyRepr(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func pyTypes(types string) string {
+	split := strings.Split(types, "|")
+	if len(split) == 1 {
+		return pyRepr(split[0])
+	}
+	return pyRepr(split)
+}
@@ -0,0 +1,221 @@
+// Package gons3test provides an in-process fake GNS3 controller for testing
+// code built on gons3 without a real GNS3 server.
+package gons3test
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"gons3"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server is a fake GNS3 controller that keeps projects, files, nodes, links,
+// drawings, snapshots and templates in memory, with a local compute that
+// provides a few images. It answers with the status codes and error
+// payloads of a GNS3 2.2 server.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	projects    *collection
+	templates   *collection
+	state       map[string]*projectState
+	subscribers map[string]map[chan notification]bool
+	nextConsole int
+	closing     chan struct{}
+}
+
+// NewServer starts a fake GNS3 controller. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		projects:    newCollection(),
+		templates:   newTemplates(),
+		state:       map[string]*projectState{},
+		subscribers: map[string]map[chan notification]bool{},
+		nextConsole: 5000,
+		closing:     make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Close ends the notification streams and shuts down the server.
+func (s *Server) Close() {
+	close(s.closing)
+	s.Server.Close()
+}
+
+// Client returns a GNS3HTTPClient connected to the fake controller.
+func (s *Server) Client() gons3.GNS3HTTPClient {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		panic(err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		panic(err)
+	}
+	return gons3.GNS3HTTPClient{
+		Client:   s.Server.Client(),
+		Scheme:   u.Scheme,
+		Hostname: u.Hostname(),
+		Port:     port,
+	}
+}
+
+// ServeHTTP routes the request to the fake controller's handlers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "v2" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	// The notification stream is long lived and locks the server as needed.
+	if len(segments) == 4 && segments[1] == "projects" && segments[3] == "notifications" {
+		s.serveNotifications(w, r, segments[2])
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch segments[1] {
+	case "version":
+		writeJSON(w, http.StatusOK, object{"version": "2.2.0", "local": true})
+	case "projects":
+		s.serveProjects(w, r, segments[2:])
+	case "templates":
+		s.serveTemplates(w, r, segments[2:])
+	case "computes":
+		s.serveComputes(w, r, segments[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// object models a JSON object of the GNS3 API.
+type object map[string]interface{}
+
+// copyObject deep copies o through JSON.
+func copyObject(o object) object {
+	data, err := json.Marshal(o)
+	if err != nil {
+		panic(err)
+	}
+	c := object{}
+	if err := json.Unmarshal(data, &c); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// collection keeps objects by id in creation order.
+type collection struct {
+	ids   []string
+	items map[string]object
+}
+
+func newCollection() *collection {
+	return &collection{items: map[string]object{}}
+}
+
+func (c *collection) add(id string, o object) {
+	if _, ok := c.items[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = o
+}
+
+func (c *collection) get(id string) (object, bool) {
+	o, ok := c.items[id]
+	return o, ok
+}
+
+func (c *collection) remove(id string) {
+	delete(c.items, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *collection) list() []object {
+	list := make([]object, 0, len(c.ids))
+	for _, id := range c.ids {
+		list = append(list, c.items[id])
+	}
+	return list
+}
+
+func (c *collection) copy() *collection {
+	n := newCollection()
+	for _, id := range c.ids {
+		n.add(id, copyObject(c.items[id]))
+	}
+	return n
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, msg string) {
+	writeJSON(w, statusCode, object{"message": msg, "status": statusCode})
+}
+
+// readJSON reads the request body into an object, writing the error response on failure.
+func readJSON(w http.ResponseWriter, r *http.Request) (object, bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	o := object{}
+	if len(body) == 0 {
+		return o, true
+	}
+	if err := json.Unmarshal(body, &o); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON "+err.Error())
+		return nil, false
+	}
+	return o, true
+}
+
+// readValidJSON reads the request body and validates it against the schema.
+func readValidJSON(w http.ResponseWriter, r *http.Request, s schema) (object, bool) {
+	o, ok := readJSON(w, r)
+	if !ok {
+		return nil, false
+	}
+	if msg := s.validate(o); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return nil, false
+	}
+	return o, true
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+}
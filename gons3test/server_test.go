@@ -0,0 +1,150 @@
+package gons3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func do(t *testing.T, s *Server, method, path string, body interface{}, expectedStatus int) object {
+	t.Helper()
+	var reader *bytes.Reader
+	if body == nil {
+		reader = bytes.NewReader(nil)
+	} else {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Error marshaling body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	if err != nil {
+		t.Fatalf("Error creating request: %v", err)
+	}
+	resp, err := s.Server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		t.Fatalf("%v %v: Expected status code: %v, got %v", method, path, expectedStatus, resp.StatusCode)
+	}
+	result := object{}
+	if resp.Header.Get("Content-Type") == "application/json" {
+		json.NewDecoder(resp.Body).Decode(&result)
+	}
+	return result
+}
+
+func TestNodesAndLinks(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	proj := do(t, s, "POST", "/v2/projects", object{"name": "TestNodesAndLinks"}, 201)
+	base := "/v2/projects/" + proj["project_id"].(string)
+
+	r1 := do(t, s, "POST", base+"/nodes", object{"name": "R1", "node_type": "qemu", "compute_id": "local", "properties": object{"adapters": 2}}, 201)
+	r2 := do(t, s, "POST", base+"/nodes", object{"name": "R2", "node_type": "vpcs", "compute_id": "local"}, 201)
+	if ports := r1["ports"].([]interface{}); len(ports) != 2 {
+		t.Errorf("Expected ports: %v, got %v", 2, len(ports))
+	}
+	if r1["console"] == nil || r1["console"] == r2["console"] {
+		t.Errorf("Expected unique consoles, got %v and %v", r1["console"], r2["console"])
+	}
+
+	do(t, s, "POST", base+"/nodes", object{"name": "R3", "node_type": "router", "compute_id": "local"}, 400)
+	do(t, s, "POST", base+"/nodes/"+r1["node_id"].(string)+"/start", nil, 200)
+	if n := do(t, s, "GET", base+"/nodes/"+r1["node_id"].(string), nil, 200); n["status"] != "started" {
+		t.Errorf("Expected status: %v, got %v", "started", n["status"])
+	}
+
+	ends := []object{
+		{"node_id": r1["node_id"], "adapter_number": 1, "port_number": 0},
+		{"node_id": r2["node_id"], "adapter_number": 0, "port_number": 0},
+	}
+	link := do(t, s, "POST", base+"/links", object{"nodes": ends}, 201)
+	do(t, s, "POST", base+"/links", object{"nodes": ends}, 409)
+	do(t, s, "POST", base+"/links", object{"nodes": ends[:1]}, 400)
+
+	linkPath := base + "/links/" + link["link_id"].(string)
+	if l := do(t, s, "POST", linkPath+"/start_capture", object{}, 201); l["capturing"] != true {
+		t.Errorf("Expected capturing: %v, got %v", true, l["capturing"])
+	}
+	do(t, s, "POST", linkPath+"/start_capture", object{}, 409)
+
+	do(t, s, "DELETE", base+"/nodes/"+r2["node_id"].(string), nil, 204)
+	do(t, s, "GET", linkPath, nil, 404)
+}
+
+func TestSnapshots(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	proj := do(t, s, "POST", "/v2/projects", object{"name": "TestSnapshots"}, 201)
+	base := "/v2/projects/" + proj["project_id"].(string)
+
+	do(t, s, "POST", base+"/nodes", object{"name": "PC1", "node_type": "vpcs", "compute_id": "local"}, 201)
+	snapshot := do(t, s, "POST", base+"/snapshots", object{"name": "before"}, 201)
+	do(t, s, "POST", base+"/snapshots", object{"name": "before"}, 409)
+	do(t, s, "POST", base+"/nodes", object{"name": "PC2", "node_type": "vpcs", "compute_id": "local"}, 201)
+
+	do(t, s, "POST", base+"/snapshots/"+snapshot["snapshot_id"].(string)+"/restore", nil, 201)
+
+	req, _ := http.NewRequest("GET", s.URL+base+"/nodes", nil)
+	resp, err := s.Server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Error getting nodes: %v", err)
+	}
+	defer resp.Body.Close()
+	nodes := []object{}
+	json.NewDecoder(resp.Body).Decode(&nodes)
+	if len(nodes) != 1 {
+		t.Errorf("Expected nodes after restore: %v, got %v", 1, len(nodes))
+	}
+}
+
+func TestProjectFileTraversal(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	proj := do(t, s, "POST", "/v2/projects", object{"name": "TestProjectFileTraversal"}, 201)
+	base := "/v2/projects/" + proj["project_id"].(string)
+
+	do(t, s, "GET", base+"/files/missing", nil, 404)
+	do(t, s, "GET", base+"/files/a/%2E%2E/%2E%2E/secret", nil, 403)
+}
+
+func TestTemplatesAndDrawings(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	templateID := s.AddTemplate(map[string]interface{}{"name": "Router", "template_type": "qemu", "adapters": 4, "ram": 512})
+	proj := do(t, s, "POST", "/v2/projects", object{"name": "TestTemplatesAndDrawings"}, 201)
+	base := "/v2/projects/" + proj["project_id"].(string)
+
+	n := do(t, s, "POST", base+"/templates/"+templateID, object{"x": 10, "y": 20}, 201)
+	if n["name"] != "Router-1" || n["template_id"] != templateID {
+		t.Errorf("Expected node Router-1 from template, got %v", n)
+	}
+	if ports := n["ports"].([]interface{}); len(ports) != 4 {
+		t.Errorf("Expected ports: %v, got %v", 4, len(ports))
+	}
+	n = do(t, s, "POST", base+"/templates/19021f99-e36f-394d-b4a1-8aaa902ab9cc", object{"x": 0, "y": 0}, 201)
+	if n["name"] != "PC1" || n["node_type"] != "vpcs" {
+		t.Errorf("Expected vpcs node PC1, got %v", n)
+	}
+	do(t, s, "DELETE", "/v2/templates/19021f99-e36f-394d-b4a1-8aaa902ab9cc", nil, 409)
+	do(t, s, "GET", "/v2/computes/local/dynamips/images", nil, 200)
+	do(t, s, "GET", "/v2/computes/vm/dynamips/images", nil, 404)
+
+	d := do(t, s, "POST", base+"/drawings", object{"svg": "<svg></svg>", "x": 5}, 201)
+	d = do(t, s, "PUT", base+"/drawings/"+d["drawing_id"].(string), object{"rotation": 90}, 201)
+	if d["rotation"] != float64(90) || d["x"] != float64(5) {
+		t.Errorf("Expected rotated drawing, got %v", d)
+	}
+	do(t, s, "DELETE", base+"/drawings/"+d["drawing_id"].(string), nil, 204)
+}
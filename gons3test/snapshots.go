@@ -0,0 +1,70 @@
+package gons3test
+
+import (
+	"net/http"
+	"time"
+)
+
+var snapshotSchema = schema{
+	properties: map[string]string{"name": "string"},
+	required:   []string{"name"},
+	strict:     true,
+}
+
+func (s *Server) serveSnapshots(w http.ResponseWriter, r *http.Request, proj object, state *projectState, segments []string) {
+	projectID := proj["project_id"].(string)
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, state.snapshots.list())
+		case "POST":
+			body, ok := readValidJSON(w, r, snapshotSchema)
+			if !ok {
+				return
+			}
+			name := body["name"].(string)
+			for _, existing := range state.snapshots.list() {
+				if existing["name"] == name {
+					writeError(w, http.StatusConflict, "The snapshot name "+name+" already exists")
+					return
+				}
+			}
+			snapshotID := newID()
+			snapshot := copyObject(object{
+				"snapshot_id": snapshotID,
+				"project_id":  projectID,
+				"name":        name,
+				"created_at":  time.Now().Unix(),
+			})
+			state.snapshots.add(snapshotID, snapshot)
+			state.saved[snapshotID] = state.copy()
+			writeJSON(w, http.StatusCreated, snapshot)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	snapshotID := segments[0]
+	if _, ok := state.snapshots.get(snapshotID); !ok {
+		writeError(w, http.StatusNotFound, "Snapshot ID "+snapshotID+" doesn't exist")
+		return
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == "DELETE":
+		state.snapshots.remove(snapshotID)
+		delete(state.saved, snapshotID)
+		w.WriteHeader(http.StatusNoContent)
+	case len(segments) == 2 && segments[1] == "restore" && r.Method == "POST":
+		saved := state.saved[snapshotID].copy()
+		state.files = saved.files
+		state.nodes = saved.nodes
+		state.links = saved.links
+		state.drawings = saved.drawings
+		proj["status"] = "opened"
+		writeJSON(w, http.StatusCreated, proj)
+	default:
+		methodNotAllowed(w)
+	}
+}
@@ -0,0 +1,222 @@
+package gons3test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// builtinTemplates are the templates every GNS3 2.2 controller provides.
+var builtinTemplates = []object{
+	{"template_id": "39e257dc-8412-3174-b6b3-0ee3ed6a43e9", "name": "Cloud", "template_type": "cloud", "category": "guest", "default_name_format": "Cloud{0}", "symbol": ":/symbols/cloud.svg"},
+	{"template_id": "df8f4ea9-33b7-3e96-86a2-c39bc9bb649c", "name": "NAT", "template_type": "nat", "category": "guest", "default_name_format": "NAT{0}", "symbol": ":/symbols/cloud.svg"},
+	{"template_id": "19021f99-e36f-394d-b4a1-8aaa902ab9cc", "name": "VPCS", "template_type": "vpcs", "category": "guest", "default_name_format": "PC{0}", "symbol": ":/symbols/vpcs_guest.svg"},
+	{"template_id": "1966b864-93e7-32d5-965f-001384eec461", "name": "Ethernet switch", "template_type": "ethernet_switch", "category": "switch", "default_name_format": "Switch{0}", "symbol": ":/symbols/ethernet_switch.svg"},
+	{"template_id": "b4503ea9-d6b6-3695-9fe4-1db3b39290b0", "name": "Ethernet hub", "template_type": "ethernet_hub", "category": "switch", "default_name_format": "Hub{0}", "symbol": ":/symbols/hub.svg"},
+}
+
+// templateFields are the template fields that are not copied into node properties.
+var templateFields = map[string]bool{
+	"template_id": true, "name": true, "category": true, "template_type": true, "compute_id": true,
+	"builtin": true, "symbol": true, "default_name_format": true, "usage": true, "console_type": true,
+	"console_auto_start": true, "port_name_format": true,
+}
+
+var templateSchema = schema{
+	properties: map[string]string{
+		"name":          "string",
+		"template_type": "string",
+		"compute_id":    "string|null",
+	},
+	required: []string{"name", "template_type"},
+	enums:    map[string][]string{"template_type": nodeTypes},
+}
+
+var templateUsageSchema = schema{
+	properties: map[string]string{
+		"x":          "integer",
+		"y":          "integer",
+		"name":       "string",
+		"compute_id": "string",
+	},
+	required: []string{"x", "y"},
+	strict:   true,
+}
+
+func newTemplates() *collection {
+	templates := newCollection()
+	for _, t := range builtinTemplates {
+		t = copyObject(t)
+		t["builtin"] = true
+		t["compute_id"] = nil
+		templates.add(t["template_id"].(string), t)
+	}
+	return templates
+}
+
+// AddTemplate adds a template, such as a qemu appliance, and returns its id.
+// Every field other than the common template fields becomes a node property.
+func (s *Server) AddTemplate(template map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addTemplate(template)
+}
+
+func (s *Server) addTemplate(template map[string]interface{}) string {
+	t := copyObject(template)
+	templateID, _ := t["template_id"].(string)
+	if templateID == "" {
+		templateID = newID()
+	}
+	defaults := object{
+		"template_id":         templateID,
+		"builtin":             false,
+		"category":            "router",
+		"compute_id":          "local",
+		"default_name_format": "{name}-{0}",
+		"symbol":              ":/symbols/router.svg",
+	}
+	for k, v := range defaults {
+		if _, ok := t[k]; !ok {
+			t[k] = v
+		}
+	}
+	s.templates.add(templateID, copyObject(t))
+	return templateID
+}
+
+func (s *Server) serveTemplates(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, s.templates.list())
+		case "POST":
+			body, ok := readValidJSON(w, r, templateSchema)
+			if !ok {
+				return
+			}
+			templateID := s.addTemplate(body)
+			t, _ := s.templates.get(templateID)
+			writeJSON(w, http.StatusCreated, t)
+		default:
+			methodNotAllowed(w)
+		}
+		return
+	}
+
+	templateID := segments[0]
+	t, ok := s.templates.get(templateID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template ID "+templateID+" doesn't exist")
+		return
+	}
+	if len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, t)
+	case "PUT":
+		if t["builtin"] == true {
+			writeError(w, http.StatusConflict, "Template ID "+templateID+" cannot be updated because it is built-in")
+			return
+		}
+		body, ok := readJSON(w, r)
+		if !ok {
+			return
+		}
+		for k, v := range body {
+			if k != "template_id" {
+				t[k] = v
+			}
+		}
+		writeJSON(w, http.StatusOK, t)
+	case "DELETE":
+		if t["builtin"] == true {
+			writeError(w, http.StatusConflict, "Template ID "+templateID+" cannot be deleted because it is built-in")
+			return
+		}
+		s.templates.remove(templateID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// serveTemplateUsage creates a node in the project from a template.
+func (s *Server) serveTemplateUsage(w http.ResponseWriter, r *http.Request, projectID string, state *projectState, segments []string) {
+	if len(segments) != 1 || r.Method != "POST" {
+		methodNotAllowed(w)
+		return
+	}
+	templateID := segments[0]
+	t, ok := s.templates.get(templateID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Template ID "+templateID+" doesn't exist")
+		return
+	}
+	body, ok := readValidJSON(w, r, templateUsageSchema)
+	if !ok {
+		return
+	}
+
+	name, _ := body["name"].(string)
+	if name == "" {
+		name = nextNodeName(state, t)
+	}
+	computeID, _ := body["compute_id"].(string)
+	if computeID == "" {
+		computeID = "local"
+	}
+
+	properties := object{}
+	for k, v := range t {
+		if !templateFields[k] {
+			properties[k] = v
+		}
+	}
+	nodeBody := object{
+		"name":       name,
+		"node_type":  t["template_type"],
+		"compute_id": computeID,
+		"symbol":     t["symbol"],
+		"properties": properties,
+		"x":          body["x"],
+		"y":          body["y"],
+	}
+	for _, k := range []string{"console_type", "console_auto_start", "port_name_format"} {
+		if v, ok := t[k]; ok {
+			nodeBody[k] = v
+		}
+	}
+
+	nodeID := newID()
+	node := s.newNode(projectID, nodeID, nodeBody)
+	node["template_id"] = templateID
+	state.nodes.add(nodeID, node)
+	s.notify(projectID, "node.created", node)
+	writeJSON(w, http.StatusCreated, node)
+}
+
+// nextNodeName names a node from the template's default_name_format.
+func nextNodeName(state *projectState, t object) string {
+	format, _ := t["default_name_format"].(string)
+	if format == "" {
+		format = "{name}-{0}"
+	}
+	name, _ := t["name"].(string)
+	format = strings.Replace(format, "{name}", name, -1)
+
+	used := map[interface{}]bool{}
+	for _, node := range state.nodes.list() {
+		used[node["name"]] = true
+	}
+	for i := 1; ; i++ {
+		candidate := strings.Replace(format, "{0}", fmt.Sprint(i), -1)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
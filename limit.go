@@ -0,0 +1,293 @@
+package gons3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LimitOptions models the limits enforced by a LimitedClient.
+// A zero MaxInFlight or RequestsPerSecond disables that limit.
+type LimitOptions struct {
+	// MaxInFlight is the maximum number of requests awaiting a response or
+	// still reading their response body.
+	MaxInFlight int
+	// RequestsPerSecond is the sustained rate at which requests are sent.
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be sent at once before
+	// RequestsPerSecond applies. It defaults to 1.
+	Burst int
+	// PerProject applies the limits to each project separately. Requests that
+	// are not made against a project share a single limit.
+	PerProject bool
+	// GlobalMaxInFlight, GlobalRequestsPerSecond and GlobalBurst limit all the
+	// requests together when PerProject is set. Zero values disable them.
+	GlobalMaxInFlight       int
+	GlobalRequestsPerSecond float64
+	GlobalBurst             int
+}
+
+// LimitedClient is a GNS3Client that limits the concurrent requests and
+// requests per second sent by the wrapped client. Requests block until they
+// are allowed to proceed or their context is done.
+type LimitedClient struct {
+	client  GNS3Client
+	options LimitOptions
+
+	mu     sync.Mutex
+	global *limiter
+	// shared limits the requests that are not made against a project when
+	// PerProject is set.
+	shared   *limiter
+	projects map[string]*limiter
+	// sweepAt is the number of project limiters at which idle ones are removed.
+	sweepAt int
+}
+
+// minSweep is the smallest number of project limiters that triggers a sweep.
+const minSweep = 64
+
+// NewLimitedClient creates a LimitedClient that wraps g.
+func NewLimitedClient(g GNS3Client, options LimitOptions) *LimitedClient {
+	if options.Burst < 1 {
+		options.Burst = 1
+	}
+	if options.GlobalBurst < 1 {
+		options.GlobalBurst = 1
+	}
+	l := &LimitedClient{
+		client:   g,
+		options:  options,
+		projects: map[string]*limiter{},
+		sweepAt:  minSweep,
+	}
+	if options.PerProject {
+		l.global = newLimiter(options.GlobalMaxInFlight, options.GlobalRequestsPerSecond, options.GlobalBurst)
+		l.shared = newLimiter(options.MaxInFlight, options.RequestsPerSecond, options.Burst)
+	} else {
+		l.global = newLimiter(options.MaxInFlight, options.RequestsPerSecond, options.Burst)
+	}
+	return l
+}
+
+// GetSchemeAuthority gets the scheme and authority of the wrapped client.
+func (l *LimitedClient) GetSchemeAuthority() string {
+	return l.client.GetSchemeAuthority()
+}
+
+// Do waits for the limits to allow the request, then sends it with the wrapped client.
+func (l *LimitedClient) Do(req *http.Request) (*http.Response, error) {
+	lim, done := l.limiterFor(req)
+	release, err := l.acquire(req.Context(), lim)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	release = chain(release, done)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		release()
+		return resp, err
+	}
+
+	// The request remains in flight until the caller is done with the body
+	resp.Body = &releaseCloser{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// acquire waits for the project or shared limiter, then for the global limiter.
+func (l *LimitedClient) acquire(ctx context.Context, lim *limiter) (func(), error) {
+	if lim == nil {
+		return l.global.acquire(ctx)
+	}
+	release, err := lim.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	releaseGlobal, err := l.global.acquire(ctx)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return chain(releaseGlobal, release), nil
+}
+
+// limiterFor returns the project or shared limiter of the request, or nil
+// when only the global limiter applies, and the function that stops using it.
+func (l *LimitedClient) limiterFor(req *http.Request) (*limiter, func()) {
+	if !l.options.PerProject {
+		return nil, func() {}
+	}
+	projectID, ok := endpointIDs(req.URL.Path)["project_id"]
+	if !ok {
+		return l.shared, func() {}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lim, ok := l.projects[projectID]
+	if !ok {
+		if len(l.projects) >= l.sweepAt {
+			l.sweep()
+		}
+		lim = newLimiter(l.options.MaxInFlight, l.options.RequestsPerSecond, l.options.Burst)
+		l.projects[projectID] = lim
+	}
+	lim.users++
+	return lim, func() {
+		l.mu.Lock()
+		lim.users--
+		l.mu.Unlock()
+	}
+}
+
+// sweep removes the project limiters that are unused and as good as new, so
+// the map only grows with the projects in use.
+func (l *LimitedClient) sweep() {
+	// The builtin delete is shadowed by the DELETE request helper
+	projects := map[string]*limiter{}
+	for projectID, lim := range l.projects {
+		if lim.users > 0 || !lim.idle() {
+			projects[projectID] = lim
+		}
+	}
+	l.projects = projects
+	l.sweepAt = 2 * len(l.projects)
+	if l.sweepAt < minSweep {
+		l.sweepAt = minSweep
+	}
+}
+
+// chain returns a function that calls every function in order.
+func chain(fns ...func()) func() {
+	return func() {
+		for _, fn := range fns {
+			fn()
+		}
+	}
+}
+
+// limiter combines a semaphore with a token bucket.
+type limiter struct {
+	slots chan struct{}
+	rate  float64
+	burst float64
+	// users is the number of requests using a project limiter, guarded by
+	// the LimitedClient's mutex.
+	users int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(maxInFlight int, rate float64, burst int) *limiter {
+	l := &limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// acquire blocks until a request may be sent and returns the function that ends it.
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if delay := l.reserve(); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.unreserve()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// refill adds the tokens earned since the last refill. l.mu must be held.
+func (l *limiter) refill() {
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// reserve takes a token from the bucket and returns how long to wait for it.
+func (l *limiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// unreserve gives back the token of a request that was cancelled while waiting for it.
+func (l *limiter) unreserve() {
+	if l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// idle reports whether no request is in flight and the bucket is full.
+func (l *limiter) idle() bool {
+	if len(l.slots) > 0 {
+		return false
+	}
+	if l.rate <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return l.tokens >= l.burst
+}
+
+// releaseCloser calls release once when the body is closed.
+type releaseCloser struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
@@ -0,0 +1,230 @@
+package gons3_test
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingClient counts the requests in flight, in total and per project,
+// and blocks each request until it is released through its project.
+// Requests whose context is already done are counted as leaked instead.
+type blockingClient struct {
+	started chan string
+
+	mu         sync.Mutex
+	leaked     int
+	release    map[string]chan struct{}
+	total      int
+	maxTotal   int
+	inFlight   map[string]int
+	maxProject map[string]int
+}
+
+func newBlockingClient() *blockingClient {
+	return &blockingClient{
+		started:    make(chan string),
+		release:    map[string]chan struct{}{},
+		inFlight:   map[string]int{},
+		maxProject: map[string]int{},
+	}
+}
+
+func (b *blockingClient) GetSchemeAuthority() string {
+	return "http://gns3.test"
+}
+
+// releaseChan returns the channel that releases the requests of a project.
+func (b *blockingClient) releaseChan(projectID string) chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.release[projectID]; !ok {
+		b.release[projectID] = make(chan struct{})
+	}
+	return b.release[projectID]
+}
+
+func (b *blockingClient) Do(req *http.Request) (*http.Response, error) {
+	projectID := ""
+	if segments := strings.Split(req.URL.Path, "/"); len(segments) > 3 && segments[2] == "projects" {
+		projectID = segments[3]
+	}
+
+	b.mu.Lock()
+	if err := req.Context().Err(); err != nil {
+		b.leaked++
+		b.mu.Unlock()
+		return nil, err
+	}
+	b.total++
+	b.inFlight[projectID]++
+	if b.total > b.maxTotal {
+		b.maxTotal = b.total
+	}
+	if b.inFlight[projectID] > b.maxProject[projectID] {
+		b.maxProject[projectID] = b.inFlight[projectID]
+	}
+	b.mu.Unlock()
+
+	b.started <- projectID
+	<-b.releaseChan(projectID)
+
+	b.mu.Lock()
+	b.total--
+	b.inFlight[projectID]--
+	b.mu.Unlock()
+
+	resp := response(200, "application/json", `{"project_id": "`+projectID+`"}`)
+	resp.Request = req
+	return resp, nil
+}
+
+// drive runs requests through lc until limit of them are blocked, checks
+// that a cancelled request for probeID is held back by the limiter, then
+// releases the oldest request each time the next one starts.
+func drive(t *testing.T, b *blockingClient, lc *gons3.LimitedClient, projectIDs []string, limit int, probeID string) {
+	t.Helper()
+	wg := sync.WaitGroup{}
+	for _, projectID := range projectIDs {
+		wg.Add(1)
+		go func(projectID string) {
+			defer wg.Done()
+			if _, err := gons3.GetProject(lc, projectID); err != nil {
+				t.Errorf("Error getting project: %v", err)
+			}
+		}(projectID)
+	}
+
+	inFlight := []string{}
+	for i := 0; i < limit; i++ {
+		inFlight = append(inFlight, <-b.started)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gons3.GetProject(contextClient{lc, ctx}, probeID); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error: %v, got %v", context.Canceled, err)
+	}
+	if b.leaked != 0 {
+		t.Errorf("Expected the limiter to hold back the request for %v", probeID)
+	}
+	for i := limit; i < len(projectIDs); i++ {
+		b.releaseChan(inFlight[0]) <- struct{}{}
+		inFlight = append(inFlight[1:], <-b.started)
+	}
+	for _, projectID := range inFlight {
+		b.releaseChan(projectID) <- struct{}{}
+	}
+	wg.Wait()
+}
+
+const (
+	projectA = "6d3b9c44-4c1f-4f5e-9d5c-2b3d7e8f9a10"
+	projectB = "1f2e3d4c-5b6a-4798-8a9b-0c1d2e3f4a5b"
+	projectC = "9a8b7c6d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+	projectD = "2ad6ab1a-0f0e-4b1a-9b1e-3f1a2b3c4d5e"
+)
+
+func TestLimitedClientMaxInFlight(t *testing.T) {
+	b := newBlockingClient()
+	lc := gons3.NewLimitedClient(b, gons3.LimitOptions{MaxInFlight: 2})
+
+	projectIDs := []string{}
+	for i := 0; i < 10; i++ {
+		projectIDs = append(projectIDs, []string{projectA, projectB}[i%2])
+	}
+	drive(t, b, lc, projectIDs, 2, projectA)
+
+	if b.maxTotal != 2 {
+		t.Errorf("Expected max in flight: %v, got %v", 2, b.maxTotal)
+	}
+}
+
+func TestLimitedClientPerProject(t *testing.T) {
+	b := newBlockingClient()
+	lc := gons3.NewLimitedClient(b, gons3.LimitOptions{MaxInFlight: 1, PerProject: true})
+
+	drive(t, b, lc, []string{projectA, projectB, projectA, projectB, projectA, projectB}, 2, projectA)
+
+	for _, projectID := range []string{projectA, projectB} {
+		if b.maxProject[projectID] != 1 {
+			t.Errorf("Expected max in flight for %v: %v, got %v", projectID, 1, b.maxProject[projectID])
+		}
+	}
+}
+
+func TestLimitedClientPerProjectGlobal(t *testing.T) {
+	b := newBlockingClient()
+	lc := gons3.NewLimitedClient(b, gons3.LimitOptions{PerProject: true, GlobalMaxInFlight: 2})
+
+	// Only the global limit holds back a request for another project
+	drive(t, b, lc, []string{projectA, projectB, projectC, projectA, projectB, projectC}, 2, projectD)
+
+	if b.maxTotal != 2 {
+		t.Errorf("Expected max in flight: %v, got %v", 2, b.maxTotal)
+	}
+}
+
+// projectsClient answers every request with an empty project list.
+type projectsClient struct{}
+
+func (projectsClient) GetSchemeAuthority() string {
+	return "http://gns3.test"
+}
+
+func (projectsClient) Do(req *http.Request) (*http.Response, error) {
+	resp := response(200, "application/json", "[]")
+	resp.Request = req
+	return resp, nil
+}
+
+type contextClient struct {
+	gons3.GNS3Client
+	ctx context.Context
+}
+
+func (c contextClient) Do(req *http.Request) (*http.Response, error) {
+	return c.GNS3Client.Do(req.WithContext(c.ctx))
+}
+
+func TestLimitedClientBurst(t *testing.T) {
+	lc := gons3.NewLimitedClient(projectsClient{}, gons3.LimitOptions{RequestsPerSecond: 0.001, Burst: 3})
+	for i := 0; i < 3; i++ {
+		if _, err := gons3.GetProjects(lc); err != nil {
+			t.Fatalf("Error getting projects: %v", err)
+		}
+	}
+
+	// A cancelled request only fails if it has to wait for a token.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gons3.GetProjects(contextClient{lc, ctx}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error: %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestLimitedClientCancelReturnsToken(t *testing.T) {
+	lc := gons3.NewLimitedClient(projectsClient{}, gons3.LimitOptions{RequestsPerSecond: 10})
+	if _, err := gons3.GetProjects(lc); err != nil {
+		t.Fatalf("Error getting projects: %v", err)
+	}
+
+	// Without giving back their tokens, the cancelled requests would delay
+	// the next request by 10 seconds.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 100; i++ {
+		if _, err := gons3.GetProjects(contextClient{lc, cancelled}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected error: %v, got %v", context.Canceled, err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := gons3.GetProjects(contextClient{lc, ctx}); err != nil {
+		t.Errorf("Expected the cancelled requests to give back their tokens, got %v", err)
+	}
+}
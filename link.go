@@ -0,0 +1,237 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/link.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/link_handler.py
+
+package gons3
+
+import (
+	"net/url"
+)
+
+// LinkNode models a GNS3 Link's Node endpoint
+type LinkNode struct {
+	NodeID        string     `json:"node_id"`
+	AdapterNumber int        `json:"adapter_number"`
+	PortNumber    int        `json:"port_number"`
+	Label         *NodeLabel `json:"label,omitempty"`
+}
+
+// Link models an instance of a GNS3 link.
+type Link struct {
+	LinkID           string                 `json:"link_id"`
+	ProjectID        string                 `json:"project_id"`
+	Nodes            []LinkNode             `json:"nodes"`
+	LinkType         string                 `json:"link_type"`
+	Capturing        bool                   `json:"capturing"`
+	CaptureFileName  string                 `json:"capture_file_name"`
+	CaptureFilePath  string                 `json:"capture_file_path"`
+	CaptureComputeID string                 `json:"capture_compute_id"`
+	Filters          map[string]interface{} `json:"filters"`
+	Suspend          bool                   `json:"suspend"`
+}
+
+// HasNode returns true if the link is connected to the specified node.
+func (l Link) HasNode(nodeID string) bool {
+	for _, n := range l.Nodes {
+		if n.NodeID == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateLink creates a GNS3 link in the specified project.
+func CreateLink(g GNS3Client, projectID string, l LinkCreator) (Link, error) {
+	if projectID == "" {
+		return Link{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links"
+	link := Link{}
+	if err := post(g, path, 201, l.values, &link); err != nil {
+		return Link{}, err
+	}
+	return link, nil
+}
+
+// UpdateLink updates a GNS3 link.
+func UpdateLink(g GNS3Client, projectID, linkID string, l LinkUpdater) (Link, error) {
+	if projectID == "" || linkID == "" {
+		return Link{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links/" + url.PathEscape(linkID)
+	link := Link{}
+	if err := put(g, path, 201, l.values, &link); err != nil {
+		return Link{}, err
+	}
+	return link, nil
+}
+
+// DeleteLink deletes a GNS3 link.
+func DeleteLink(g GNS3Client, projectID, linkID string) error {
+	if projectID == "" || linkID == "" {
+		return ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links/" + url.PathEscape(linkID)
+	if err := delete(g, path, 204, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLink gets a GNS3 link.
+func GetLink(g GNS3Client, projectID, linkID string) (Link, error) {
+	if projectID == "" || linkID == "" {
+		return Link{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links/" + url.PathEscape(linkID)
+	link := Link{}
+	if err := get(g, path, 200, &link); err != nil {
+		return Link{}, err
+	}
+	return link, nil
+}
+
+// GetLinks gets all the GNS3 links in the specified project.
+func GetLinks(g GNS3Client, projectID string) ([]Link, error) {
+	if projectID == "" {
+		return []Link{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links"
+	links := []Link{}
+	if err := get(g, path, 200, &links); err != nil {
+		return []Link{}, err
+	}
+	return links, nil
+}
+
+// GetNodeLinks gets all the GNS3 links connected to the specified node.
+func GetNodeLinks(g GNS3Client, projectID, nodeID string) ([]Link, error) {
+	if projectID == "" || nodeID == "" {
+		return []Link{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/links"
+	links := []Link{}
+	if err := get(g, path, 200, &links); err != nil {
+		return []Link{}, err
+	}
+	return links, nil
+}
+
+// StartLinkCapture starts a packet capture on a GNS3 link.
+func StartLinkCapture(g GNS3Client, projectID, linkID string, c LinkCapture) (Link, error) {
+	if projectID == "" || linkID == "" {
+		return Link{}, ErrEmptyID
+	}
+
+	values := c.values
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links/" + url.PathEscape(linkID) + "/start_capture"
+	link := Link{}
+	if err := post(g, path, 201, values, &link); err != nil {
+		return Link{}, err
+	}
+	return link, nil
+}
+
+// StopLinkCapture stops the packet capture on a GNS3 link.
+func StopLinkCapture(g GNS3Client, projectID, linkID string) (Link, error) {
+	if projectID == "" || linkID == "" {
+		return Link{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/links/" + url.PathEscape(linkID) + "/stop_capture"
+	link := Link{}
+	if err := post(g, path, 201, nil, &link); err != nil {
+		return Link{}, err
+	}
+	return link, nil
+}
+
+// LinkCreator models a new GNS3 link.
+type LinkCreator struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the link.
+func (l *LinkCreator) SetProperty(name string, value interface{}) {
+	if l.values == nil {
+		l.values = map[string]interface{}{}
+	}
+	l.values[name] = value
+}
+
+// AddNode adds a node endpoint to the new link. A link has two endpoints.
+func (l *LinkCreator) AddNode(nodeID string, adapterNumber, portNumber int) {
+	nodes, _ := l.values["nodes"].([]LinkNode)
+	nodes = append(nodes, LinkNode{NodeID: nodeID, AdapterNumber: adapterNumber, PortNumber: portNumber})
+	l.SetProperty("nodes", nodes)
+}
+
+// SetLinkID sets the link_id for the new link.
+func (l *LinkCreator) SetLinkID(linkID string) {
+	l.SetProperty("link_id", linkID)
+}
+
+// SetSuspend sets the suspend option for the new link.
+func (l *LinkCreator) SetSuspend(suspend bool) {
+	l.SetProperty("suspend", suspend)
+}
+
+// SetFilters sets the packet filters for the new link.
+func (l *LinkCreator) SetFilters(filters map[string]interface{}) {
+	l.SetProperty("filters", filters)
+}
+
+// LinkUpdater models an update to a GNS3 link.
+type LinkUpdater struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the link.
+func (l *LinkUpdater) SetProperty(name string, value interface{}) {
+	if l.values == nil {
+		l.values = map[string]interface{}{}
+	}
+	l.values[name] = value
+}
+
+// SetSuspend sets the suspend option for the link.
+func (l *LinkUpdater) SetSuspend(suspend bool) {
+	l.SetProperty("suspend", suspend)
+}
+
+// SetFilters sets the packet filters for the link.
+func (l *LinkUpdater) SetFilters(filters map[string]interface{}) {
+	l.SetProperty("filters", filters)
+}
+
+// LinkCapture models the options of a GNS3 link packet capture.
+type LinkCapture struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the capture.
+func (l *LinkCapture) SetProperty(name string, value interface{}) {
+	if l.values == nil {
+		l.values = map[string]interface{}{}
+	}
+	l.values[name] = value
+}
+
+// SetCaptureFileName sets the capture_file_name for the capture.
+func (l *LinkCapture) SetCaptureFileName(name string) {
+	l.SetProperty("capture_file_name", name)
+}
+
+// SetDataLinkType sets the data_link_type for the capture, such as "DLT_EN10MB".
+func (l *LinkCapture) SetDataLinkType(dataLinkType string) {
+	l.SetProperty("data_link_type", dataLinkType)
+}
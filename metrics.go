@@ -0,0 +1,166 @@
+package gons3
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Error classes recorded by Metrics.
+const (
+	ErrorClassRequestFailed = "request_failed"
+	ErrorClassTimeout       = "timeout"
+	ErrorClassClientError   = "client_error"
+	ErrorClassServerError   = "server_error"
+)
+
+// LatencyBuckets are the upper bounds of the latency histogram kept by Metrics.
+var LatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// EndpointStats models the recorded statistics of a single method and endpoint template.
+type EndpointStats struct {
+	Method        string            `json:"method"`
+	Endpoint      string            `json:"endpoint"`
+	Count         uint64            `json:"count"`
+	Errors        map[string]uint64 `json:"errors"`
+	TotalDuration time.Duration     `json:"total_duration"`
+	MaxDuration   time.Duration     `json:"max_duration"`
+	Buckets       []uint64          `json:"buckets"`
+}
+
+// Metrics records request counts, latencies and error classes per endpoint template.
+// Metrics implements expvar.Var, so it can be published with expvar.Publish.
+// The zero value is ready to use.
+type Metrics struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointStats
+}
+
+// Observe records a single request to path that finished with the status code or error.
+func (m *Metrics) Observe(method, path string, statusCode int, err error, duration time.Duration) {
+	endpoint := EndpointTemplate(path)
+	key := method + " " + endpoint
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.endpoints == nil {
+		m.endpoints = map[string]*EndpointStats{}
+	}
+	stats, ok := m.endpoints[key]
+	if !ok {
+		stats = &EndpointStats{
+			Method:   method,
+			Endpoint: endpoint,
+			Errors:   map[string]uint64{},
+			Buckets:  make([]uint64, len(LatencyBuckets)),
+		}
+		m.endpoints[key] = stats
+	}
+
+	stats.Count++
+	stats.TotalDuration += duration
+	if duration > stats.MaxDuration {
+		stats.MaxDuration = duration
+	}
+	for i, bound := range LatencyBuckets {
+		if duration <= bound {
+			stats.Buckets[i]++
+		}
+	}
+	if class := errorClass(statusCode, err); class != "" {
+		stats.Errors[class]++
+	}
+}
+
+// Snapshot returns a copy of the recorded statistics sorted by endpoint and method.
+func (m *Metrics) Snapshot() []EndpointStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]EndpointStats, 0, len(m.endpoints))
+	for _, stats := range m.endpoints {
+		s := *stats
+		s.Errors = make(map[string]uint64, len(stats.Errors))
+		for class, count := range stats.Errors {
+			s.Errors[class] = count
+		}
+		s.Buckets = append([]uint64{}, stats.Buckets...)
+		snapshot = append(snapshot, s)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Endpoint != snapshot[j].Endpoint {
+			return snapshot[i].Endpoint < snapshot[j].Endpoint
+		}
+		return snapshot[i].Method < snapshot[j].Method
+	})
+	return snapshot
+}
+
+// String returns the recorded statistics as JSON.
+func (m *Metrics) String() string {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "null"
+	}
+	return string(data)
+}
+
+func errorClass(statusCode int, err error) string {
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassRequestFailed
+	}
+	switch {
+	case statusCode >= 500:
+		return ErrorClassServerError
+	case statusCode >= 400:
+		return ErrorClassClientError
+	}
+	return ""
+}
+
+// MetricsClient is a GNS3Client that records every request sent by Client into Metrics.
+// Latencies are measured until the response headers are received.
+type MetricsClient struct {
+	Client  GNS3Client
+	Metrics *Metrics
+}
+
+// GetSchemeAuthority gets the scheme and authority of the wrapped client.
+func (m MetricsClient) GetSchemeAuthority() string {
+	return m.Client.GetSchemeAuthority()
+}
+
+// Do sends the HTTP request with the wrapped client and records the result.
+func (m MetricsClient) Do(req *http.Request) (*http.Response, error) {
+	if m.Metrics == nil {
+		return m.Client.Do(req)
+	}
+
+	start := time.Now()
+	resp, err := m.Client.Do(req)
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	m.Metrics.Observe(req.Method, req.URL.Path, statusCode, err, time.Since(start))
+	return resp, err
+}
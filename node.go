@@ -0,0 +1,435 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/node.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/node_handler.py
+
+package gons3
+
+import (
+	"io"
+	"net/url"
+)
+
+// NodePort models a GNS3 Node's Port
+type NodePort struct {
+	Name          string            `json:"name"`
+	ShortName     string            `json:"short_name"`
+	AdapterNumber int               `json:"adapter_number"`
+	PortNumber    int               `json:"port_number"`
+	LinkType      string            `json:"link_type"`
+	DataLinkTypes map[string]string `json:"data_link_types"`
+}
+
+// NodeLabel models a GNS3 Node's Label
+type NodeLabel struct {
+	Text     string `json:"text"`
+	Style    string `json:"style"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Rotation int    `json:"rotation"`
+}
+
+// Node models an instance of a GNS3 node.
+type Node struct {
+	Name             string                 `json:"name"`
+	NodeID           string                 `json:"node_id"`
+	ProjectID        string                 `json:"project_id"`
+	NodeType         string                 `json:"node_type"`
+	ComputeID        string                 `json:"compute_id"`
+	TemplateID       string                 `json:"template_id"`
+	Status           string                 `json:"status"`
+	Console          int                    `json:"console"`
+	ConsoleHost      string                 `json:"console_host"`
+	ConsoleType      string                 `json:"console_type"`
+	ConsoleAutoStart bool                   `json:"console_auto_start"`
+	CommandLine      string                 `json:"command_line"`
+	NodeDirectory    string                 `json:"node_directory"`
+	Properties       map[string]interface{} `json:"properties"`
+	Symbol           string                 `json:"symbol"`
+	Label            *NodeLabel             `json:"label"`
+	Locked           bool                   `json:"locked"`
+	X                int                    `json:"x"`
+	Y                int                    `json:"y"`
+	Z                int                    `json:"z"`
+	PortNameFormat   string                 `json:"port_name_format"`
+	PortSegmentSize  int                    `json:"port_segment_size"`
+	FirstPortName    string                 `json:"first_port_name"`
+	Ports            []NodePort             `json:"ports"`
+}
+
+// IsStarted returns true if the node status is set to started.
+func (n Node) IsStarted() bool {
+	return n.Status == "started"
+}
+
+// IsStopped returns true if the node status is set to stopped.
+func (n Node) IsStopped() bool {
+	return n.Status == "stopped"
+}
+
+// IsSuspended returns true if the node status is set to suspended.
+func (n Node) IsSuspended() bool {
+	return n.Status == "suspended"
+}
+
+// PortByName returns the node's port with the specified name or short name.
+func (n Node) PortByName(name string) (NodePort, bool) {
+	for _, p := range n.Ports {
+		if p.Name == name || p.ShortName == name {
+			return p, true
+		}
+	}
+	return NodePort{}, false
+}
+
+// CreateNode creates a GNS3 node in the specified project.
+func CreateNode(g GNS3Client, projectID string, n NodeCreator) (Node, error) {
+	if projectID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes"
+	node := Node{}
+	if err := post(g, path, 201, n.values, &node); err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// UpdateNode updates a GNS3 node.
+func UpdateNode(g GNS3Client, projectID, nodeID string, n NodeUpdater) (Node, error) {
+	if projectID == "" || nodeID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID)
+	node := Node{}
+	if err := put(g, path, 200, n.values, &node); err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// DeleteNode deletes a GNS3 node.
+func DeleteNode(g GNS3Client, projectID, nodeID string) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID)
+	if err := delete(g, path, 204, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetNode gets a GNS3 node.
+func GetNode(g GNS3Client, projectID, nodeID string) (Node, error) {
+	if projectID == "" || nodeID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID)
+	node := Node{}
+	if err := get(g, path, 200, &node); err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// GetNodes gets all the GNS3 nodes in the specified project.
+func GetNodes(g GNS3Client, projectID string) ([]Node, error) {
+	if projectID == "" {
+		return []Node{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes"
+	nodes := []Node{}
+	if err := get(g, path, 200, &nodes); err != nil {
+		return []Node{}, err
+	}
+	return nodes, nil
+}
+
+// StartNode starts a GNS3 node.
+func StartNode(g GNS3Client, projectID, nodeID string) (Node, error) {
+	return nodeAction(g, projectID, nodeID, "start")
+}
+
+// StopNode stops a GNS3 node.
+func StopNode(g GNS3Client, projectID, nodeID string) (Node, error) {
+	return nodeAction(g, projectID, nodeID, "stop")
+}
+
+// SuspendNode suspends a GNS3 node.
+func SuspendNode(g GNS3Client, projectID, nodeID string) (Node, error) {
+	return nodeAction(g, projectID, nodeID, "suspend")
+}
+
+// ReloadNode reloads a GNS3 node.
+func ReloadNode(g GNS3Client, projectID, nodeID string) (Node, error) {
+	return nodeAction(g, projectID, nodeID, "reload")
+}
+
+// IsolateNode suspends every link connected to a GNS3 node, taking it off the
+// network without stopping it.
+func IsolateNode(g GNS3Client, projectID, nodeID string) error {
+	return nodeLinksAction(g, projectID, nodeID, "isolate")
+}
+
+// UnisolateNode resumes every link connected to a GNS3 node.
+func UnisolateNode(g GNS3Client, projectID, nodeID string) error {
+	return nodeLinksAction(g, projectID, nodeID, "unisolate")
+}
+
+func nodeLinksAction(g GNS3Client, projectID, nodeID, action string) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/" + action
+	return post(g, path, 204, map[string]interface{}{}, nil)
+}
+
+// DuplicateNode duplicates a stopped GNS3 node, with its files, at the
+// specified position. The server names the copy after the original.
+func DuplicateNode(g GNS3Client, projectID, nodeID string, x, y, z int) (Node, error) {
+	if projectID == "" || nodeID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/duplicate"
+	body := map[string]interface{}{"x": x, "y": y, "z": z}
+	node := Node{}
+	if err := post(g, path, 201, body, &node); err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+func nodeAction(g GNS3Client, projectID, nodeID, action string) (Node, error) {
+	if projectID == "" || nodeID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/" + action
+	node := Node{}
+	if err := post(g, path, 200, map[string]interface{}{}, &node); err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// ReadNodeFile reads a file in a GNS3 node's directory.
+func ReadNodeFile(g GNS3Client, projectID, nodeID, filepath string) ([]byte, error) {
+	if projectID == "" || nodeID == "" {
+		return []byte{}, ErrEmptyID
+	}
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/files/" + escaped
+	data := []byte{}
+	if err := get(g, path, 200, &data); err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// ReadNodeFileTo streams a file in a GNS3 node's directory to w. The
+// progress function, if not nil, is called as the file is received.
+func ReadNodeFileTo(g GNS3Client, projectID, nodeID, filepath string, w io.Writer, progress ProgressFunc) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return err
+	}
+
+	if progress != nil {
+		w = &progressWriter{w: w, total: -1, progress: progress}
+	}
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/files/" + escaped
+	return get(g, path, 200, w)
+}
+
+// WriteNodeFile writes a file in a GNS3 node's directory, such as a startup config.
+func WriteNodeFile(g GNS3Client, projectID, nodeID, filepath string, data []byte) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return err
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/files/" + escaped
+	if err := post(g, path, 201, &data, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteNodeFileFrom streams r to a file in a GNS3 node's directory. The
+// progress function, if not nil, is called as the file is sent.
+func WriteNodeFileFrom(g GNS3Client, projectID, nodeID, filepath string, r io.Reader, progress ProgressFunc) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return err
+	}
+
+	if progress != nil {
+		r = &progressReader{r: r, total: readerSize(r), progress: progress}
+	}
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/files/" + escaped
+	return post(g, path, 201, r, nil)
+}
+
+// NodeCreator models a new GNS3 node.
+type NodeCreator struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the node.
+func (n *NodeCreator) SetProperty(name string, value interface{}) {
+	if n.values == nil {
+		n.values = map[string]interface{}{}
+	}
+	n.values[name] = value
+}
+
+// SetNodeProperty sets a node type specific value in the properties of the node.
+func (n *NodeCreator) SetNodeProperty(name string, value interface{}) {
+	properties, ok := n.values["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+	}
+	properties[name] = value
+	n.SetProperty("properties", properties)
+}
+
+// SetName sets the name for the new node.
+func (n *NodeCreator) SetName(name string) {
+	n.SetProperty("name", name)
+}
+
+// SetNodeType sets the node_type for the new node, such as "qemu" or "vpcs".
+func (n *NodeCreator) SetNodeType(nodeType string) {
+	n.SetProperty("node_type", nodeType)
+}
+
+// SetComputeID sets the compute_id for the new node.
+func (n *NodeCreator) SetComputeID(computeID string) {
+	n.SetProperty("compute_id", computeID)
+}
+
+// SetNodeID sets the node_id for the new node.
+func (n *NodeCreator) SetNodeID(nodeID string) {
+	n.SetProperty("node_id", nodeID)
+}
+
+// SetConsole sets the console port for the new node.
+func (n *NodeCreator) SetConsole(console int) {
+	n.SetProperty("console", console)
+}
+
+// SetConsoleType sets the console_type for the new node.
+func (n *NodeCreator) SetConsoleType(consoleType string) {
+	n.SetProperty("console_type", consoleType)
+}
+
+// SetConsoleAutoStart sets the console_auto_start option for the new node.
+func (n *NodeCreator) SetConsoleAutoStart(consoleAutoStart bool) {
+	n.SetProperty("console_auto_start", consoleAutoStart)
+}
+
+// SetSymbol sets the symbol for the new node.
+func (n *NodeCreator) SetSymbol(symbol string) {
+	n.SetProperty("symbol", symbol)
+}
+
+// SetLocked sets the locked option for the new node.
+func (n *NodeCreator) SetLocked(locked bool) {
+	n.SetProperty("locked", locked)
+}
+
+// SetPosition sets the x, y and z position for the new node.
+func (n *NodeCreator) SetPosition(x, y, z int) {
+	n.SetProperty("x", x)
+	n.SetProperty("y", y)
+	n.SetProperty("z", z)
+}
+
+// SetPortNameFormat sets the port_name_format option for the new node.
+func (n *NodeCreator) SetPortNameFormat(portNameFormat string) {
+	n.SetProperty("port_name_format", portNameFormat)
+}
+
+// NodeUpdater models an update to a GNS3 node.
+type NodeUpdater struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the node.
+func (n *NodeUpdater) SetProperty(name string, value interface{}) {
+	if n.values == nil {
+		n.values = map[string]interface{}{}
+	}
+	n.values[name] = value
+}
+
+// SetNodeProperty sets a node type specific value in the properties of the node.
+func (n *NodeUpdater) SetNodeProperty(name string, value interface{}) {
+	properties, ok := n.values["properties"].(map[string]interface{})
+	if !ok {
+		properties = map[string]interface{}{}
+	}
+	properties[name] = value
+	n.SetProperty("properties", properties)
+}
+
+// SetName sets the name for the node.
+func (n *NodeUpdater) SetName(name string) {
+	n.SetProperty("name", name)
+}
+
+// SetConsole sets the console port for the node.
+func (n *NodeUpdater) SetConsole(console int) {
+	n.SetProperty("console", console)
+}
+
+// SetConsoleType sets the console_type for the node.
+func (n *NodeUpdater) SetConsoleType(consoleType string) {
+	n.SetProperty("console_type", consoleType)
+}
+
+// SetConsoleAutoStart sets the console_auto_start option for the node.
+func (n *NodeUpdater) SetConsoleAutoStart(consoleAutoStart bool) {
+	n.SetProperty("console_auto_start", consoleAutoStart)
+}
+
+// SetSymbol sets the symbol for the node.
+func (n *NodeUpdater) SetSymbol(symbol string) {
+	n.SetProperty("symbol", symbol)
+}
+
+// SetLocked sets the locked option for the node.
+func (n *NodeUpdater) SetLocked(locked bool) {
+	n.SetProperty("locked", locked)
+}
+
+// SetPosition sets the x, y and z position for the node.
+func (n *NodeUpdater) SetPosition(x, y, z int) {
+	n.SetProperty("x", x)
+	n.SetProperty("y", y)
+	n.SetProperty("z", z)
+}
+
+// SetPortNameFormat sets the port_name_format option for the node.
+func (n *NodeUpdater) SetPortNameFormat(portNameFormat string) {
+	n.SetProperty("port_name_format", portNameFormat)
+}
@@ -0,0 +1,74 @@
+package gons3
+
+import (
+	"io"
+	"os"
+)
+
+// ProgressFunc is called as a file transfer progresses with the number of
+// bytes transferred and the total size, or -1 if the size is unknown.
+type ProgressFunc func(transferred, total int64)
+
+// progressReader reports the bytes read from a request body.
+type progressReader struct {
+	r           io.Reader
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		p.progress(p.transferred, p.total)
+	}
+	return n, err
+}
+
+func (p *progressReader) size() int64 {
+	return p.total
+}
+
+// progressWriter reports the bytes written from a response body.
+type progressWriter struct {
+	w           io.Writer
+	transferred int64
+	total       int64
+	progress    ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		p.progress(p.transferred, p.total)
+	}
+	return n, err
+}
+
+// setSize is called by req with the Content-Length of the response.
+func (p *progressWriter) setSize(size int64) {
+	p.total = size
+}
+
+// readerSize returns the number of bytes left in r, or -1 if it is unknown.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ size() int64 }:
+		return r.size()
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
@@ -5,6 +5,7 @@ package gons3
 
 import (
 	"errors"
+	"io"
 	"net/url"
 )
 
@@ -147,11 +148,12 @@ func ReadProjectFile(g GNS3Client, projectID string, filepath string) ([]byte, e
 	if projectID == "" {
 		return []byte{}, ErrEmptyID
 	}
-	if filepath == "" {
-		return []byte{}, ErrEmptyFilepath
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return []byte{}, err
 	}
 
-	path := "/v2/projects/" + url.PathEscape(projectID) + "/files/" + filepath
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/files/" + escaped
 	data := []byte{}
 	if err := get(g, path, 200, &data); err != nil {
 		return []byte{}, err
@@ -164,17 +166,121 @@ func WriteProjectFile(g GNS3Client, projectID string, filepath string, data []by
 	if projectID == "" {
 		return ErrEmptyID
 	}
-	if filepath == "" {
-		return ErrEmptyFilepath
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return err
 	}
 
-	path := "/v2/projects/" + url.PathEscape(projectID) + "/files/" + filepath
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/files/" + escaped
 	if err := post(g, path, 200, &data, nil); err != nil {
 		return err
 	}
 	return nil
 }
 
+// ReadProjectFileTo streams a GNS3 project's file to w. The progress
+// function, if not nil, is called as the file is received.
+func ReadProjectFileTo(g GNS3Client, projectID, filepath string, w io.Writer, progress ProgressFunc) error {
+	if projectID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return err
+	}
+
+	if progress != nil {
+		w = &progressWriter{w: w, total: -1, progress: progress}
+	}
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/files/" + escaped
+	return get(g, path, 200, w)
+}
+
+// WriteProjectFileFrom streams r to a GNS3 project's file. The progress
+// function, if not nil, is called as the file is sent.
+func WriteProjectFileFrom(g GNS3Client, projectID, filepath string, r io.Reader, progress ProgressFunc) error {
+	if projectID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(filepath)
+	if err != nil {
+		return err
+	}
+
+	if progress != nil {
+		r = &progressReader{r: r, total: readerSize(r), progress: progress}
+	}
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/files/" + escaped
+	return post(g, path, 200, r, nil)
+}
+
+// ExportProject exports a GNS3 project as a portable zip archive.
+func ExportProject(g GNS3Client, projectID string) ([]byte, error) {
+	if projectID == "" {
+		return []byte{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/export"
+	data := []byte{}
+	if err := get(g, path, 200, &data); err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// ExportProjectTo streams the portable zip archive of a GNS3 project to w.
+// The progress function, if not nil, is called as the archive is received.
+func ExportProjectTo(g GNS3Client, projectID string, w io.Writer, progress ProgressFunc) error {
+	if projectID == "" {
+		return ErrEmptyID
+	}
+
+	if progress != nil {
+		w = &progressWriter{w: w, total: -1, progress: progress}
+	}
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/export"
+	return get(g, path, 200, w)
+}
+
+// ImportProject imports a GNS3 project from a zip archive created by
+// ExportProject. The new project gets the specified id and name.
+func ImportProject(g GNS3Client, projectID, name string, data []byte) (Project, error) {
+	if projectID == "" {
+		return Project{}, ErrEmptyID
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/import"
+	if name != "" {
+		path += "?name=" + url.QueryEscape(name)
+	}
+	proj := Project{}
+	if err := post(g, path, 201, &data, &proj); err != nil {
+		return Project{}, err
+	}
+	return proj, nil
+}
+
+// ImportProjectFrom imports a GNS3 project from a zip archive streamed from r.
+// The progress function, if not nil, is called as the archive is sent.
+func ImportProjectFrom(g GNS3Client, projectID, name string, r io.Reader, progress ProgressFunc) (Project, error) {
+	if projectID == "" {
+		return Project{}, ErrEmptyID
+	}
+
+	if progress != nil {
+		r = &progressReader{r: r, total: readerSize(r), progress: progress}
+	}
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/import"
+	if name != "" {
+		path += "?name=" + url.QueryEscape(name)
+	}
+	proj := Project{}
+	if err := post(g, path, 201, r, &proj); err != nil {
+		return Project{}, err
+	}
+	return proj, nil
+}
+
 // ProjectCreator models a new GNS3 project.
 type ProjectCreator struct {
 	values map[string]interface{}
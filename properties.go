@@ -0,0 +1,229 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/qemu.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/docker.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/vpcs.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/iou.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/dynamips_vm.py
+
+package gons3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedNodeType is returned when a node type has no typed properties.
+var ErrUnsupportedNodeType = errors.New("unsupported node type")
+
+// ErrFailedToDecodeProperties is returned when node properties could not be decoded.
+var ErrFailedToDecodeProperties = errors.New("failed to decode node properties")
+
+// NodeProperties is implemented by the typed properties of each node type.
+// Their fields are pointers so that zero values, such as a false
+// linked_clone, can be set. Nil fields are left out.
+type NodeProperties interface {
+	NodeType() string
+}
+
+// Bool returns a pointer to v, for the fields of the typed node properties.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to v, for the fields of the typed node properties.
+func Int(v int) *int {
+	return &v
+}
+
+// String returns a pointer to v, for the fields of the typed node properties.
+func String(v string) *string {
+	return &v
+}
+
+// QemuProperties models the properties of a qemu node.
+type QemuProperties struct {
+	QemuPath          *string `json:"qemu_path,omitempty"`
+	Platform          *string `json:"platform,omitempty"`
+	RAM               *int    `json:"ram,omitempty"`
+	CPUs              *int    `json:"cpus,omitempty"`
+	Adapters          *int    `json:"adapters,omitempty"`
+	AdapterType       *string `json:"adapter_type,omitempty"`
+	MACAddress        *string `json:"mac_address,omitempty"`
+	HdaDiskImage      *string `json:"hda_disk_image,omitempty"`
+	HdaDiskInterface  *string `json:"hda_disk_interface,omitempty"`
+	HdbDiskImage      *string `json:"hdb_disk_image,omitempty"`
+	HdbDiskInterface  *string `json:"hdb_disk_interface,omitempty"`
+	HdcDiskImage      *string `json:"hdc_disk_image,omitempty"`
+	HdcDiskInterface  *string `json:"hdc_disk_interface,omitempty"`
+	HddDiskImage      *string `json:"hdd_disk_image,omitempty"`
+	HddDiskInterface  *string `json:"hdd_disk_interface,omitempty"`
+	CdromImage        *string `json:"cdrom_image,omitempty"`
+	BootPriority      *string `json:"boot_priority,omitempty"`
+	KernelImage       *string `json:"kernel_image,omitempty"`
+	Initrd            *string `json:"initrd,omitempty"`
+	KernelCommandLine *string `json:"kernel_command_line,omitempty"`
+	Options           *string `json:"options,omitempty"`
+	OnClose           *string `json:"on_close,omitempty"`
+	LinkedClone       *bool   `json:"linked_clone,omitempty"`
+	Usage             *string `json:"usage,omitempty"`
+}
+
+// NodeType returns qemu.
+func (QemuProperties) NodeType() string {
+	return "qemu"
+}
+
+// DockerProperties models the properties of a docker node.
+type DockerProperties struct {
+	Image             *string `json:"image,omitempty"`
+	ContainerID       *string `json:"container_id,omitempty"`
+	Adapters          *int    `json:"adapters,omitempty"`
+	StartCommand      *string `json:"start_command,omitempty"`
+	Environment       *string `json:"environment,omitempty"`
+	ConsoleResolution *string `json:"console_resolution,omitempty"`
+	ConsoleHTTPPort   *int    `json:"console_http_port,omitempty"`
+	ConsoleHTTPPath   *string `json:"console_http_path,omitempty"`
+	ExtraHosts        *string `json:"extra_hosts,omitempty"`
+	// ExtraVolumes is left out when nil, an empty slice clears the volumes.
+	ExtraVolumes []string `json:"extra_volumes"`
+	Usage        *string  `json:"usage,omitempty"`
+}
+
+// NodeType returns docker.
+func (DockerProperties) NodeType() string {
+	return "docker"
+}
+
+// VPCSProperties models the properties of a vpcs node.
+type VPCSProperties struct {
+	StartupScript     *string `json:"startup_script,omitempty"`
+	StartupScriptPath *string `json:"startup_script_path,omitempty"`
+}
+
+// NodeType returns vpcs.
+func (VPCSProperties) NodeType() string {
+	return "vpcs"
+}
+
+// IOUProperties models the properties of an iou node.
+type IOUProperties struct {
+	Path                 *string `json:"path,omitempty"`
+	MD5Sum               *string `json:"md5sum,omitempty"`
+	SerialAdapters       *int    `json:"serial_adapters,omitempty"`
+	EthernetAdapters     *int    `json:"ethernet_adapters,omitempty"`
+	RAM                  *int    `json:"ram,omitempty"`
+	NVRAM                *int    `json:"nvram,omitempty"`
+	L1Keepalives         *bool   `json:"l1_keepalives,omitempty"`
+	UseDefaultIOUValues  *bool   `json:"use_default_iou_values,omitempty"`
+	StartupConfigContent *string `json:"startup_config_content,omitempty"`
+	PrivateConfigContent *string `json:"private_config_content,omitempty"`
+	ApplicationID        *int    `json:"application_id,omitempty"`
+	Usage                *string `json:"usage,omitempty"`
+}
+
+// NodeType returns iou.
+func (IOUProperties) NodeType() string {
+	return "iou"
+}
+
+// DynamipsProperties models the properties of a dynamips node.
+type DynamipsProperties struct {
+	Platform             *string `json:"platform,omitempty"`
+	Image                *string `json:"image,omitempty"`
+	ImageMD5Sum          *string `json:"image_md5sum,omitempty"`
+	RAM                  *int    `json:"ram,omitempty"`
+	NVRAM                *int    `json:"nvram,omitempty"`
+	IdlePC               *string `json:"idlepc,omitempty"`
+	IdleMax              *int    `json:"idlemax,omitempty"`
+	IdleSleep            *int    `json:"idlesleep,omitempty"`
+	ExecArea             *int    `json:"exec_area,omitempty"`
+	Mmap                 *bool   `json:"mmap,omitempty"`
+	Sparsemem            *bool   `json:"sparsemem,omitempty"`
+	MACAddress           *string `json:"mac_address,omitempty"`
+	Chassis              *string `json:"chassis,omitempty"`
+	Midplane             *string `json:"midplane,omitempty"`
+	NPE                  *string `json:"npe,omitempty"`
+	Slot0                *string `json:"slot0,omitempty"`
+	Slot1                *string `json:"slot1,omitempty"`
+	Slot2                *string `json:"slot2,omitempty"`
+	Slot3                *string `json:"slot3,omitempty"`
+	Slot4                *string `json:"slot4,omitempty"`
+	Slot5                *string `json:"slot5,omitempty"`
+	Slot6                *string `json:"slot6,omitempty"`
+	WIC0                 *string `json:"wic0,omitempty"`
+	WIC1                 *string `json:"wic1,omitempty"`
+	WIC2                 *string `json:"wic2,omitempty"`
+	StartupConfigContent *string `json:"startup_config_content,omitempty"`
+	AutoDeleteDisks      *bool   `json:"auto_delete_disks,omitempty"`
+	Usage                *string `json:"usage,omitempty"`
+}
+
+// NodeType returns dynamips.
+func (DynamipsProperties) NodeType() string {
+	return "dynamips"
+}
+
+// DecodeProperties decodes the properties of the node into the typed
+// properties of its node type, such as *QemuProperties for a qemu node.
+// Properties without a typed field are ignored, and the fields of missing
+// properties are nil.
+func (n Node) DecodeProperties() (NodeProperties, error) {
+	var properties NodeProperties
+	switch n.NodeType {
+	case "qemu":
+		properties = &QemuProperties{}
+	case "docker":
+		properties = &DockerProperties{}
+	case "vpcs":
+		properties = &VPCSProperties{}
+	case "iou":
+		properties = &IOUProperties{}
+	case "dynamips":
+		properties = &DynamipsProperties{}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedNodeType, n.NodeType)
+	}
+
+	data, err := json.Marshal(n.Properties)
+	if err != nil {
+		return nil, Wrap(ErrFailedToDecodeProperties, err)
+	}
+	if err := json.Unmarshal(data, properties); err != nil {
+		return nil, Wrap(ErrFailedToDecodeProperties, err)
+	}
+	return properties, nil
+}
+
+// propertyValues converts the non nil fields of typed properties to
+// property values.
+func propertyValues(p NodeProperties) map[string]interface{} {
+	values := map[string]interface{}{}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return values
+	}
+	all := map[string]interface{}{}
+	json.Unmarshal(data, &all)
+	for name, value := range all {
+		if value != nil {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// SetNodeProperties sets the node type and the typed properties of the new
+// node. Nil fields are left out.
+func (n *NodeCreator) SetNodeProperties(p NodeProperties) {
+	n.SetNodeType(p.NodeType())
+	for name, value := range propertyValues(p) {
+		n.SetNodeProperty(name, value)
+	}
+}
+
+// SetNodeProperties sets the typed properties of the node. Nil fields are left out.
+func (n *NodeUpdater) SetNodeProperties(p NodeProperties) {
+	for name, value := range propertyValues(p) {
+		n.SetNodeProperty(name, value)
+	}
+}
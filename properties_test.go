@@ -0,0 +1,25 @@
+package gons3_test
+
+import (
+	"errors"
+	"gons3"
+	"reflect"
+	"testing"
+)
+
+func TestDecodePropertiesTypeMismatch(t *testing.T) {
+	node := gons3.Node{NodeType: "iou", Properties: map[string]interface{}{"ethernet_adapters": "two"}}
+	if _, err := node.DecodeProperties(); !errors.Is(err, gons3.ErrFailedToDecodeProperties) {
+		t.Errorf("Expected error: %v, got %v", gons3.ErrFailedToDecodeProperties, err)
+	}
+
+	node = gons3.Node{NodeType: "dynamips", Properties: map[string]interface{}{"platform": "c7200", "idlepc": "0x60606f54", "slot0": "C7200-IO-FE"}}
+	properties, err := node.DecodeProperties()
+	if err != nil {
+		t.Fatalf("Error decoding properties: %v", err)
+	}
+	expected := &gons3.DynamipsProperties{Platform: gons3.String("c7200"), IdlePC: gons3.String("0x60606f54"), Slot0: gons3.String("C7200-IO-FE")}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("Expected properties: %+v, got %+v", expected, properties)
+	}
+}
@@ -0,0 +1,158 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/qemu.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/node_handler.py
+
+package gons3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidDiskSlot means that the disk slot is not one of hda, hdb, hdc or hdd.
+var ErrInvalidDiskSlot = errors.New("invalid disk slot")
+
+// qemuDiskSlots are the disk slots of a qemu node.
+var qemuDiskSlots = []string{"hda", "hdb", "hdc", "hdd"}
+
+// checkQemuDiskSlot returns ErrInvalidDiskSlot unless slot is in qemuDiskSlots.
+func checkQemuDiskSlot(slot string) error {
+	for _, s := range qemuDiskSlots {
+		if s == slot {
+			return nil
+		}
+	}
+	return Wrap(ErrInvalidDiskSlot, fmt.Errorf("%q is not one of %v", slot, strings.Join(qemuDiskSlots, ", ")))
+}
+
+// QemuDisk models a disk image attached to a qemu node.
+type QemuDisk struct {
+	// Slot is the disk slot, one of hda, hdb, hdc or hdd.
+	Slot      string
+	Image     string
+	Interface string
+	MD5Sum    string
+}
+
+// QemuDisks returns the disk images attached to a qemu node, ordered by slot.
+func (n Node) QemuDisks() []QemuDisk {
+	disks := []QemuDisk{}
+	for _, slot := range qemuDiskSlots {
+		image, _ := n.Properties[slot+"_disk_image"].(string)
+		if image == "" {
+			continue
+		}
+		disk := QemuDisk{Slot: slot, Image: image}
+		disk.Interface, _ = n.Properties[slot+"_disk_interface"].(string)
+		disk.MD5Sum, _ = n.Properties[slot+"_disk_image_md5sum"].(string)
+		disks = append(disks, disk)
+	}
+	return disks
+}
+
+// GetQemuDisks gets the disk images attached to a qemu node.
+func GetQemuDisks(g GNS3Client, projectID, nodeID string) ([]QemuDisk, error) {
+	node, err := GetNode(g, projectID, nodeID)
+	if err != nil {
+		return []QemuDisk{}, err
+	}
+	return node.QemuDisks(), nil
+}
+
+// AttachQemuDisk attaches a disk image to a slot of a stopped qemu node, such
+// as hdb. The interface, such as virtio, is left unchanged when empty.
+func AttachQemuDisk(g GNS3Client, projectID, nodeID, slot, image, diskInterface string) (Node, error) {
+	if err := checkQemuDiskSlot(slot); err != nil {
+		return Node{}, err
+	}
+	u := NodeUpdater{}
+	u.SetNodeProperty(slot+"_disk_image", image)
+	if diskInterface != "" {
+		u.SetNodeProperty(slot+"_disk_interface", diskInterface)
+	}
+	return UpdateNode(g, projectID, nodeID, u)
+}
+
+// DetachQemuDisk detaches the disk image from a slot of a stopped qemu node.
+func DetachQemuDisk(g GNS3Client, projectID, nodeID, slot string) (Node, error) {
+	if err := checkQemuDiskSlot(slot); err != nil {
+		return Node{}, err
+	}
+	u := NodeUpdater{}
+	u.SetNodeProperty(slot+"_disk_image", "")
+	return UpdateNode(g, projectID, nodeID, u)
+}
+
+// CreateQemuDiskImage creates a disk image in the directory of a qemu node.
+// Older GNS3 servers do not provide this endpoint.
+func CreateQemuDiskImage(g GNS3Client, projectID, nodeID, name string, d QemuDiskImageCreator) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(name)
+	if err != nil {
+		return err
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/qemu/disk_image/" + escaped
+	return post(g, path, 201, d.values, nil)
+}
+
+// ExtendQemuDiskImage grows a disk image in the directory of a qemu node by
+// extend megabytes. Older GNS3 servers do not provide this endpoint.
+func ExtendQemuDiskImage(g GNS3Client, projectID, nodeID, name string, extend int) error {
+	if projectID == "" || nodeID == "" {
+		return ErrEmptyID
+	}
+	escaped, err := escapeFilepath(name)
+	if err != nil {
+		return err
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/nodes/" + url.PathEscape(nodeID) + "/qemu/disk_image/" + escaped
+	return put(g, path, 201, map[string]interface{}{"extend": extend}, nil)
+}
+
+// QemuDiskImageCreator models a new qemu disk image.
+type QemuDiskImageCreator struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the new disk image, such
+// as the cluster_size of a qcow2 image.
+func (d *QemuDiskImageCreator) SetProperty(name string, value interface{}) {
+	if d.values == nil {
+		d.values = map[string]interface{}{}
+	}
+	d.values[name] = value
+}
+
+// SetFormat sets the format for the new disk image, such as qcow2 or raw.
+func (d *QemuDiskImageCreator) SetFormat(format string) {
+	d.SetProperty("format", format)
+}
+
+// SetSize sets the size in megabytes for the new disk image.
+func (d *QemuDiskImageCreator) SetSize(size int) {
+	d.SetProperty("size", size)
+}
+
+// SetPreallocation sets the preallocation mode for the new disk image, such as off or metadata.
+func (d *QemuDiskImageCreator) SetPreallocation(preallocation string) {
+	d.SetProperty("preallocation", preallocation)
+}
+
+// SetLazyRefcounts sets the lazy_refcounts option for the new qcow2 disk image.
+func (d *QemuDiskImageCreator) SetLazyRefcounts(lazyRefcounts bool) {
+	if lazyRefcounts {
+		d.SetProperty("lazy_refcounts", "on")
+	} else {
+		d.SetProperty("lazy_refcounts", "off")
+	}
+}
+
+// SetAdapterType sets the adapter_type for the new vmdk disk image.
+func (d *QemuDiskImageCreator) SetAdapterType(adapterType string) {
+	d.SetProperty("adapter_type", adapterType)
+}
@@ -0,0 +1,140 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/schemas/template.py
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/template_handler.py
+
+package gons3
+
+import (
+	"net/url"
+)
+
+// Template models a GNS3 template used to create nodes.
+type Template struct {
+	TemplateID        string `json:"template_id"`
+	Name              string `json:"name"`
+	Category          string `json:"category"`
+	TemplateType      string `json:"template_type"`
+	ComputeID         string `json:"compute_id"`
+	Builtin           bool   `json:"builtin"`
+	Symbol            string `json:"symbol"`
+	DefaultNameFormat string `json:"default_name_format"`
+}
+
+// GetTemplates gets all the GNS3 templates.
+func GetTemplates(g GNS3Client) ([]Template, error) {
+	path := "/v2/templates"
+	templates := []Template{}
+	if err := get(g, path, 200, &templates); err != nil {
+		return []Template{}, err
+	}
+	return templates, nil
+}
+
+// GetTemplate gets a GNS3 template instance with the specified id.
+func GetTemplate(g GNS3Client, templateID string) (Template, error) {
+	if templateID == "" {
+		return Template{}, ErrEmptyID
+	}
+
+	path := "/v2/templates/" + url.PathEscape(templateID)
+	template := Template{}
+	if err := get(g, path, 200, &template); err != nil {
+		return Template{}, err
+	}
+	return template, nil
+}
+
+// UpdateTemplate updates a GNS3 template. Built-in templates cannot be updated.
+func UpdateTemplate(g GNS3Client, templateID string, t TemplateUpdater) (Template, error) {
+	if templateID == "" {
+		return Template{}, ErrEmptyID
+	}
+
+	path := "/v2/templates/" + url.PathEscape(templateID)
+	template := Template{}
+	if err := put(g, path, 200, t.values, &template); err != nil {
+		return Template{}, err
+	}
+	return template, nil
+}
+
+// CreateNodeFromTemplate creates a GNS3 node in the specified project from a template.
+func CreateNodeFromTemplate(g GNS3Client, projectID, templateID string, t TemplateUsage) (Node, error) {
+	if projectID == "" || templateID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	values := map[string]interface{}{"x": 0, "y": 0}
+	for name, value := range t.values {
+		values[name] = value
+	}
+
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/templates/" + url.PathEscape(templateID)
+	node := Node{}
+	if err := post(g, path, 201, values, &node); err != nil {
+		return Node{}, err
+	}
+	return node, nil
+}
+
+// TemplateUsage models a new GNS3 node created from a template.
+type TemplateUsage struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the new node.
+func (t *TemplateUsage) SetProperty(name string, value interface{}) {
+	if t.values == nil {
+		t.values = map[string]interface{}{}
+	}
+	t.values[name] = value
+}
+
+// SetName sets the name for the new node.
+func (t *TemplateUsage) SetName(name string) {
+	t.SetProperty("name", name)
+}
+
+// SetComputeID sets the compute_id for the new node.
+func (t *TemplateUsage) SetComputeID(computeID string) {
+	t.SetProperty("compute_id", computeID)
+}
+
+// SetPosition sets the x and y position for the new node.
+func (t *TemplateUsage) SetPosition(x, y int) {
+	t.SetProperty("x", x)
+	t.SetProperty("y", y)
+}
+
+// TemplateUpdater models an update to a GNS3 template.
+type TemplateUpdater struct {
+	values map[string]interface{}
+}
+
+// SetProperty sets a custom property and value for the template, such as
+// the ram of a qemu template.
+func (t *TemplateUpdater) SetProperty(name string, value interface{}) {
+	if t.values == nil {
+		t.values = map[string]interface{}{}
+	}
+	t.values[name] = value
+}
+
+// SetName sets the name for the template.
+func (t *TemplateUpdater) SetName(name string) {
+	t.SetProperty("name", name)
+}
+
+// SetCategory sets the category for the template.
+func (t *TemplateUpdater) SetCategory(category string) {
+	t.SetProperty("category", category)
+}
+
+// SetSymbol sets the symbol for the template.
+func (t *TemplateUpdater) SetSymbol(symbol string) {
+	t.SetProperty("symbol", symbol)
+}
+
+// SetDefaultNameFormat sets the default_name_format for the template.
+func (t *TemplateUpdater) SetDefaultNameFormat(defaultNameFormat string) {
+	t.SetProperty("default_name_format", defaultNameFormat)
+}
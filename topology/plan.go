@@ -0,0 +1,630 @@
+package topology
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gons3"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of change an action makes.
+type Kind string
+
+// Action kinds.
+const (
+	Create Kind = "create"
+	Update Kind = "update"
+	Delete Kind = "delete"
+)
+
+var kindSymbols = map[Kind]string{Create: "+", Update: "~", Delete: "-"}
+
+// Action is a change to converge the project to the spec.
+type Action struct {
+	Kind     Kind
+	Resource string   // "project", "node", "link" or "drawing"
+	Name     string   // Project or node name, link endpoints or drawing position.
+	Changes  []string // Fields changed by an update.
+
+	apply func(a *applier) error
+}
+
+// String returns the action as "+ node PC1" or "~ node R1 (x, y)".
+func (a Action) String() string {
+	s := kindSymbols[a.Kind] + " " + a.Resource + " " + a.Name
+	if len(a.Changes) > 0 {
+		s += " (" + strings.Join(a.Changes, ", ") + ")"
+	}
+	return s
+}
+
+// Plan is the ordered list of actions that converges a project to a spec.
+type Plan struct {
+	Spec      *Spec
+	ProjectID string // Empty if the project will be created.
+	Actions   []Action
+
+	g     gons3.GNS3Client
+	nodes map[string]gons3.Node
+}
+
+// IsEmpty returns true if the project already matches the spec.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Actions) == 0
+}
+
+// String returns the actions of the plan, one per line.
+func (p *Plan) String() string {
+	if p.IsEmpty() {
+		return "no changes\n"
+	}
+	var b strings.Builder
+	for _, a := range p.Actions {
+		b.WriteString(a.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Apply executes the actions of the plan in order, stopping at the first error.
+func (p *Plan) Apply() error {
+	a := &applier{g: p.g, projectID: p.ProjectID, nodes: map[string]gons3.Node{}}
+	for name, node := range p.nodes {
+		a.nodes[name] = node
+	}
+	for _, action := range p.Actions {
+		if err := action.apply(a); err != nil {
+			return fmt.Errorf("topology: %s: %w", action, err)
+		}
+	}
+	p.ProjectID = a.projectID
+	return nil
+}
+
+// ErrProjectClosed is returned by NewPlan when the project of the spec is
+// closed, because the resources of closed projects cannot be listed.
+var ErrProjectClosed = errors.New("topology: project is closed")
+
+// Apply opens the project of the spec if it is closed, then plans and
+// applies the spec, returning the applied plan.
+func Apply(g gons3.GNS3Client, spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	proj, found, err := findProject(g, spec.Project.Name)
+	if err != nil {
+		return nil, err
+	}
+	if found && !proj.IsOpened() {
+		if _, err := gons3.OpenProject(g, proj.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := NewPlan(g, spec)
+	if err != nil {
+		return nil, err
+	}
+	return plan, plan.Apply()
+}
+
+// applier keeps the state resolved while the actions are applied.
+type applier struct {
+	g         gons3.GNS3Client
+	projectID string
+	nodes     map[string]gons3.Node
+}
+
+// NewPlan compares the spec with the project of the same name without
+// changing anything on the server. It returns ErrProjectClosed if the project
+// is closed. Nodes whose template changed are replaced.
+func NewPlan(g gons3.GNS3Client, spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	templates, err := resolveTemplates(g, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Spec: spec, g: g, nodes: map[string]gons3.Node{}}
+	proj, found, err := findProject(g, spec.Project.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		plan.add(Action{Kind: Create, Resource: "project", Name: spec.Project.Name, apply: createProject(spec)})
+		for _, n := range spec.Nodes {
+			plan.add(createNodeAction(n, templates[n.Name]))
+		}
+		for _, l := range spec.Links {
+			plan.add(createLinkAction(l))
+		}
+		for _, d := range spec.Drawings {
+			plan.add(createDrawingAction(d))
+		}
+		return plan, nil
+	}
+
+	if !proj.IsOpened() {
+		return nil, fmt.Errorf("%w: %q", ErrProjectClosed, proj.Name)
+	}
+	plan.ProjectID = proj.ProjectID
+
+	nodes, err := gons3.GetNodes(g, proj.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := gons3.GetLinks(g, proj.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	drawings, err := gons3.GetDrawings(g, proj.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes are matched by name, nodes not in the spec or with another
+	// template are deleted along with their links.
+	specNodes := map[string]NodeSpec{}
+	for _, n := range spec.Nodes {
+		specNodes[n.Name] = n
+	}
+	liveNodes := map[string]gons3.Node{}
+	names := map[string]string{}
+	removed := map[string]bool{}
+	for _, node := range nodes {
+		names[node.NodeID] = node.Name
+		n, ok := specNodes[node.Name]
+		if !ok || node.TemplateID != templates[n.Name] {
+			removed[node.NodeID] = true
+			continue
+		}
+		liveNodes[node.Name] = node
+	}
+
+	// Links are matched by their endpoints.
+	liveLinks := map[string]gons3.Link{}
+	for _, link := range links {
+		key, ok := liveLinkKey(link, nodes, names)
+		if !ok || linkRemoved(link, removed) {
+			plan.add(Action{Kind: Delete, Resource: "link", Name: key, apply: deleteLink(link.LinkID)})
+			continue
+		}
+		liveLinks[key] = link
+	}
+	specLinks := map[string]bool{}
+	var linkActions []Action
+	usedPorts := map[Endpoint]Endpoint{}
+	for _, l := range spec.Links {
+		if err := checkLivePorts(l, liveNodes, usedPorts); err != nil {
+			return nil, err
+		}
+		key, err := specLinkKey(l, liveNodes)
+		if err != nil {
+			return nil, err
+		}
+		specLinks[key] = true
+		link, ok := liveLinks[key]
+		switch {
+		case !ok:
+			linkActions = append(linkActions, createLinkAction(l))
+		case link.Suspend != l.Suspend:
+			linkActions = append(linkActions, Action{Kind: Update, Resource: "link", Name: key, Changes: []string{"suspend"}, apply: updateLink(link.LinkID, l.Suspend)})
+		}
+	}
+	for _, link := range links {
+		key, _ := liveLinkKey(link, nodes, names)
+		if _, ok := liveLinks[key]; ok && !specLinks[key] {
+			plan.add(Action{Kind: Delete, Resource: "link", Name: key, apply: deleteLink(link.LinkID)})
+		}
+	}
+
+	for _, node := range nodes {
+		if removed[node.NodeID] {
+			plan.add(Action{Kind: Delete, Resource: "node", Name: node.Name, apply: deleteNode(node.NodeID)})
+		}
+	}
+
+	// Drawings are matched by content, unmatched drawings are replaced.
+	specDrawings := map[DrawingSpec]int{}
+	for _, d := range spec.Drawings {
+		specDrawings[d]++
+	}
+	for _, drawing := range drawings {
+		d := DrawingSpec{SVG: drawing.SVG, X: drawing.X, Y: drawing.Y, Z: drawing.Z, Rotation: drawing.Rotation, Locked: drawing.Locked}
+		if specDrawings[d] > 0 {
+			specDrawings[d]--
+			continue
+		}
+		plan.add(Action{Kind: Delete, Resource: "drawing", Name: drawingName(d), apply: deleteDrawing(drawing.DrawingID)})
+	}
+
+	if changes, u := projectChanges(spec, proj); len(changes) > 0 {
+		plan.add(Action{Kind: Update, Resource: "project", Name: spec.Project.Name, Changes: changes, apply: updateProject(u)})
+	}
+
+	for _, n := range spec.Nodes {
+		node, ok := liveNodes[n.Name]
+		if !ok {
+			plan.add(createNodeAction(n, templates[n.Name]))
+			continue
+		}
+		plan.nodes[n.Name] = node
+		if changes, u := nodeChanges(n, node); len(changes) > 0 {
+			plan.add(Action{Kind: Update, Resource: "node", Name: n.Name, Changes: changes, apply: updateNode(n.Name, u)})
+		}
+	}
+
+	for _, a := range linkActions {
+		plan.add(a)
+	}
+	for _, d := range spec.Drawings {
+		if specDrawings[d] > 0 {
+			specDrawings[d]--
+			plan.add(createDrawingAction(d))
+		}
+	}
+	return plan, nil
+}
+
+func (p *Plan) add(a Action) {
+	p.Actions = append(p.Actions, a)
+}
+
+// resolveTemplates maps the spec's node names to template ids.
+func resolveTemplates(g gons3.GNS3Client, spec *Spec) (map[string]string, error) {
+	if len(spec.Nodes) == 0 {
+		return map[string]string{}, nil
+	}
+	templates, err := gons3.GetTemplates(g)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]string{}
+	for _, n := range spec.Nodes {
+		for _, t := range templates {
+			if t.TemplateID == n.Template || t.Name == n.Template {
+				ids[n.Name] = t.TemplateID
+				break
+			}
+		}
+		if ids[n.Name] == "" {
+			return nil, fmt.Errorf("topology: node %q uses unknown template %q", n.Name, n.Template)
+		}
+	}
+	return ids, nil
+}
+
+func findProject(g gons3.GNS3Client, name string) (gons3.Project, bool, error) {
+	projects, err := gons3.GetProjects(g)
+	if err != nil {
+		return gons3.Project{}, false, err
+	}
+	for _, p := range projects {
+		if p.Name == name {
+			return p, true, nil
+		}
+	}
+	return gons3.Project{}, false, nil
+}
+
+// liveLinkKey returns the endpoints of a live link as a key like the spec links.
+func liveLinkKey(link gons3.Link, nodes []gons3.Node, names map[string]string) (string, bool) {
+	ok := len(link.Nodes) == 2
+	ends := make([]string, 0, len(link.Nodes))
+	for _, ln := range link.Nodes {
+		port := fmt.Sprintf("%d/%d", ln.AdapterNumber, ln.PortNumber)
+		for _, node := range nodes {
+			if node.NodeID != ln.NodeID {
+				continue
+			}
+			for _, p := range node.Ports {
+				if p.AdapterNumber == ln.AdapterNumber && p.PortNumber == ln.PortNumber {
+					port = p.Name
+				}
+			}
+		}
+		ends = append(ends, Endpoint{Node: names[ln.NodeID], Port: port}.String())
+	}
+	sort.Strings(ends)
+	return strings.Join(ends, " <-> "), ok
+}
+
+// specLinkKey returns the endpoints of a spec link, with the port names of
+// existing nodes resolved from their short names.
+func specLinkKey(l LinkSpec, nodes map[string]gons3.Node) (string, error) {
+	ends := []string{}
+	for _, e := range []Endpoint{l.A, l.B} {
+		if node, ok := nodes[e.Node]; ok {
+			p, ok := node.PortByName(e.Port)
+			if !ok {
+				return "", fmt.Errorf("topology: node %q has no port %q", e.Node, e.Port)
+			}
+			e.Port = p.Name
+		}
+		ends = append(ends, e.String())
+	}
+	sort.Strings(ends)
+	return strings.Join(ends, " <-> "), nil
+}
+
+// checkLivePorts resolves the ports of existing nodes, so a port used under
+// two of its names, such as "e0" and "Ethernet0", is reported.
+func checkLivePorts(l LinkSpec, nodes map[string]gons3.Node, used map[Endpoint]Endpoint) error {
+	for _, e := range []Endpoint{l.A, l.B} {
+		node, ok := nodes[e.Node]
+		if !ok {
+			continue
+		}
+		p, ok := node.PortByName(e.Port)
+		if !ok {
+			continue
+		}
+		resolved := Endpoint{Node: e.Node, Port: p.Name}
+		if other, ok := used[resolved]; ok {
+			return fmt.Errorf("topology: ports %s and %s are the same interface", other, e)
+		}
+		used[resolved] = e
+	}
+	return nil
+}
+
+func linkRemoved(link gons3.Link, removed map[string]bool) bool {
+	for _, ln := range link.Nodes {
+		if removed[ln.NodeID] {
+			return true
+		}
+	}
+	return false
+}
+
+func drawingName(d DrawingSpec) string {
+	return fmt.Sprintf("at (%d, %d)", d.X, d.Y)
+}
+
+// projectSettings returns the project settings managed by the spec.
+func projectSettings(p ProjectSpec) map[string]interface{} {
+	settings := map[string]interface{}{}
+	for name, value := range map[string]interface{}{
+		"auto_close":            p.AutoClose,
+		"auto_open":             p.AutoOpen,
+		"auto_start":            p.AutoStart,
+		"scene_height":          p.SceneHeight,
+		"scene_width":           p.SceneWidth,
+		"zoom":                  p.Zoom,
+		"show_layers":           p.ShowLayers,
+		"snap_to_grid":          p.SnapToGrid,
+		"show_grid":             p.ShowGrid,
+		"grid_size":             p.GridSize,
+		"show_interface_labels": p.ShowInterfaceLabels,
+	} {
+		if v := reflect.ValueOf(value); !v.IsNil() {
+			settings[name] = v.Elem().Interface()
+		}
+	}
+	return settings
+}
+
+// projectChanges compares the managed project settings.
+func projectChanges(spec *Spec, proj gons3.Project) ([]string, gons3.ProjectUpdater) {
+	live := map[string]interface{}{
+		"auto_close":            proj.AutoClose,
+		"auto_open":             proj.AutoOpen,
+		"auto_start":            proj.AutoStart,
+		"scene_height":          proj.SceneHeight,
+		"scene_width":           proj.SceneWidth,
+		"zoom":                  proj.Zoom,
+		"show_layers":           proj.ShowLayers,
+		"snap_to_grid":          proj.SnapToGrid,
+		"show_grid":             proj.ShowGrid,
+		"grid_size":             proj.GridSize,
+		"show_interface_labels": proj.ShowInterfaceLabels,
+	}
+	u := gons3.ProjectUpdater{}
+	changes := []string{}
+	for name, want := range projectSettings(spec.Project) {
+		if want != live[name] {
+			changes = append(changes, name)
+			u.SetProperty(name, want)
+		}
+	}
+	sort.Strings(changes)
+
+	if spec.Variables != nil {
+		variables := map[string]string{}
+		if proj.Variables != nil {
+			for _, v := range *proj.Variables {
+				variables[v.Name] = v.Value
+			}
+		}
+		if !reflect.DeepEqual(variables, spec.Variables) {
+			changes = append(changes, "variables")
+			u.SetVariables(projectVariables(spec.Variables))
+		}
+	}
+	return changes, u
+}
+
+func projectVariables(variables map[string]string) []gons3.ProjectVariables {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]gons3.ProjectVariables, len(names))
+	for i, name := range names {
+		list[i] = gons3.ProjectVariables{Name: name, Value: variables[name]}
+	}
+	return list
+}
+
+// nodeChanges compares the managed node settings.
+func nodeChanges(n NodeSpec, node gons3.Node) ([]string, gons3.NodeUpdater) {
+	u := gons3.NodeUpdater{}
+	changes := []string{}
+	z := node.Z
+	if n.Z != 0 {
+		z = n.Z
+	}
+	if n.X != node.X || n.Y != node.Y || z != node.Z {
+		changes = append(changes, "position")
+		u.SetPosition(n.X, n.Y, z)
+	}
+	if n.ConsoleType != "" && n.ConsoleType != node.ConsoleType {
+		changes = append(changes, "console_type")
+		u.SetConsoleType(n.ConsoleType)
+	}
+	names := make([]string, 0, len(n.Properties))
+	for name := range n.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := normalize(n.Properties[name])
+		if !reflect.DeepEqual(want, node.Properties[name]) {
+			changes = append(changes, "properties."+name)
+			u.SetNodeProperty(name, want)
+		}
+	}
+	return changes, u
+}
+
+// normalize converts a value to its JSON decoded form so that it compares
+// with the values returned by the server.
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var n interface{}
+	if err := json.Unmarshal(data, &n); err != nil {
+		return v
+	}
+	return n
+}
+
+func createProject(spec *Spec) func(a *applier) error {
+	return func(a *applier) error {
+		c := gons3.ProjectCreator{}
+		c.SetName(spec.Project.Name)
+		for name, value := range projectSettings(spec.Project) {
+			c.SetProperty(name, value)
+		}
+		if spec.Variables != nil {
+			c.SetVariables(projectVariables(spec.Variables))
+		}
+		proj, err := gons3.CreateProject(a.g, c)
+		if err != nil {
+			return err
+		}
+		a.projectID = proj.ProjectID
+		return nil
+	}
+}
+
+func updateProject(u gons3.ProjectUpdater) func(a *applier) error {
+	return func(a *applier) error {
+		_, err := gons3.UpdateProject(a.g, a.projectID, u)
+		return err
+	}
+}
+
+func createNodeAction(n NodeSpec, templateID string) Action {
+	return Action{Kind: Create, Resource: "node", Name: n.Name, apply: func(a *applier) error {
+		t := gons3.TemplateUsage{}
+		t.SetName(n.Name)
+		t.SetPosition(n.X, n.Y)
+		if n.ComputeID != "" {
+			t.SetComputeID(n.ComputeID)
+		}
+		node, err := gons3.CreateNodeFromTemplate(a.g, a.projectID, templateID, t)
+		if err != nil {
+			return err
+		}
+		if changes, u := nodeChanges(n, node); len(changes) > 0 {
+			if node, err = gons3.UpdateNode(a.g, a.projectID, node.NodeID, u); err != nil {
+				return err
+			}
+		}
+		a.nodes[n.Name] = node
+		return nil
+	}}
+}
+
+func updateNode(name string, u gons3.NodeUpdater) func(a *applier) error {
+	return func(a *applier) error {
+		node, err := gons3.UpdateNode(a.g, a.projectID, a.nodes[name].NodeID, u)
+		if err != nil {
+			return err
+		}
+		a.nodes[name] = node
+		return nil
+	}
+}
+
+func deleteNode(nodeID string) func(a *applier) error {
+	return func(a *applier) error {
+		return gons3.DeleteNode(a.g, a.projectID, nodeID)
+	}
+}
+
+func createLinkAction(l LinkSpec) Action {
+	key, _ := specLinkKey(l, nil)
+	return Action{Kind: Create, Resource: "link", Name: key, apply: func(a *applier) error {
+		c := gons3.LinkCreator{}
+		for _, e := range []Endpoint{l.A, l.B} {
+			node, ok := a.nodes[e.Node]
+			if !ok {
+				return fmt.Errorf("node %q was not created", e.Node)
+			}
+			p, ok := node.PortByName(e.Port)
+			if !ok {
+				return fmt.Errorf("node %q has no port %q", e.Node, e.Port)
+			}
+			c.AddNode(node.NodeID, p.AdapterNumber, p.PortNumber)
+		}
+		if l.Suspend {
+			c.SetSuspend(true)
+		}
+		_, err := gons3.CreateLink(a.g, a.projectID, c)
+		return err
+	}}
+}
+
+func updateLink(linkID string, suspend bool) func(a *applier) error {
+	return func(a *applier) error {
+		u := gons3.LinkUpdater{}
+		u.SetSuspend(suspend)
+		_, err := gons3.UpdateLink(a.g, a.projectID, linkID, u)
+		return err
+	}
+}
+
+func deleteLink(linkID string) func(a *applier) error {
+	return func(a *applier) error {
+		return gons3.DeleteLink(a.g, a.projectID, linkID)
+	}
+}
+
+func createDrawingAction(d DrawingSpec) Action {
+	return Action{Kind: Create, Resource: "drawing", Name: drawingName(d), apply: func(a *applier) error {
+		c := gons3.DrawingCreator{}
+		c.SetSVG(d.SVG)
+		c.SetPosition(d.X, d.Y, d.Z)
+		c.SetRotation(d.Rotation)
+		c.SetLocked(d.Locked)
+		_, err := gons3.CreateDrawing(a.g, a.projectID, c)
+		return err
+	}}
+}
+
+func deleteDrawing(drawingID string) func(a *applier) error {
+	return func(a *applier) error {
+		return gons3.DeleteDrawing(a.g, a.projectID, drawingID)
+	}
+}
@@ -0,0 +1,177 @@
+// Package topology converges a GNS3 project to a declarative topology spec.
+//
+// A spec describes the project settings, the nodes created from templates,
+// the links between named node ports and the drawings of a lab. NewPlan
+// compares a spec with the live project and Apply issues the create, update
+// and delete calls that make the project match it, so labs can be kept in
+// version control and rebuilt on any GNS3 server.
+//
+// Specs are JSON documents:
+//
+//	{
+//	  "project": {"name": "lab", "auto_close": false},
+//	  "variables": {"domain": "example.com"},
+//	  "nodes": [
+//	    {"name": "PC1", "template": "VPCS", "x": -100},
+//	    {"name": "SW1", "template": "Ethernet switch"}
+//	  ],
+//	  "links": [
+//	    {"a": {"node": "PC1", "port": "Ethernet0"}, "b": {"node": "SW1", "port": "Ethernet0"}}
+//	  ]
+//	}
+package topology
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode"
+)
+
+// Spec describes the desired state of a GNS3 project.
+type Spec struct {
+	Project   ProjectSpec       `json:"project"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Nodes     []NodeSpec        `json:"nodes,omitempty"`
+	Links     []LinkSpec        `json:"links,omitempty"`
+	Drawings  []DrawingSpec     `json:"drawings,omitempty"`
+}
+
+// ProjectSpec describes the project settings. Settings left nil are not managed.
+type ProjectSpec struct {
+	Name                string `json:"name"`
+	AutoClose           *bool  `json:"auto_close,omitempty"`
+	AutoOpen            *bool  `json:"auto_open,omitempty"`
+	AutoStart           *bool  `json:"auto_start,omitempty"`
+	SceneHeight         *int   `json:"scene_height,omitempty"`
+	SceneWidth          *int   `json:"scene_width,omitempty"`
+	Zoom                *int   `json:"zoom,omitempty"`
+	ShowLayers          *bool  `json:"show_layers,omitempty"`
+	SnapToGrid          *bool  `json:"snap_to_grid,omitempty"`
+	ShowGrid            *bool  `json:"show_grid,omitempty"`
+	GridSize            *int   `json:"grid_size,omitempty"`
+	ShowInterfaceLabels *bool  `json:"show_interface_labels,omitempty"`
+}
+
+// NodeSpec describes a node created from a template. Template is the name or
+// id of the template. Properties are node type specific settings, only the
+// listed properties are managed.
+type NodeSpec struct {
+	Name        string                 `json:"name"`
+	Template    string                 `json:"template"`
+	ComputeID   string                 `json:"compute_id,omitempty"`
+	ConsoleType string                 `json:"console_type,omitempty"`
+	X           int                    `json:"x"`
+	Y           int                    `json:"y"`
+	Z           int                    `json:"z,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Endpoint is a port of a node, by node name and port name or short name.
+type Endpoint struct {
+	Node string `json:"node"`
+	Port string `json:"port"`
+}
+
+// String returns the endpoint as "node:port".
+func (e Endpoint) String() string {
+	return e.Node + ":" + e.Port
+}
+
+// LinkSpec describes a link between two endpoints.
+type LinkSpec struct {
+	A       Endpoint `json:"a"`
+	B       Endpoint `json:"b"`
+	Suspend bool     `json:"suspend,omitempty"`
+}
+
+// DrawingSpec describes a drawing. Drawings have no name, they are matched
+// by their content and position.
+type DrawingSpec struct {
+	SVG      string `json:"svg"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Z        int    `json:"z,omitempty"`
+	Rotation int    `json:"rotation,omitempty"`
+	Locked   bool   `json:"locked,omitempty"`
+}
+
+// Parse parses and validates a JSON spec. Unknown fields are rejected.
+func Parse(data []byte) (*Spec, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+	spec := &Spec{}
+	if err := d.Decode(spec); err != nil {
+		return nil, fmt.Errorf("topology: %v", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// LoadFile reads, parses and validates a JSON spec file.
+func LoadFile(filename string) (*Spec, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Validate checks that the spec is complete and that its links reference
+// declared nodes.
+func (s *Spec) Validate() error {
+	if s.Project.Name == "" {
+		return errors.New("topology: project name is required")
+	}
+
+	nodes := map[string]bool{}
+	for i, n := range s.Nodes {
+		if n.Name == "" {
+			return fmt.Errorf("topology: node %d has no name", i)
+		}
+		if nodes[n.Name] {
+			return fmt.Errorf("topology: node %q is declared more than once", n.Name)
+		}
+		if n.Template == "" {
+			return fmt.Errorf("topology: node %q has no template", n.Name)
+		}
+		nodes[n.Name] = true
+	}
+
+	ports := map[Endpoint]Endpoint{}
+	for i, l := range s.Links {
+		for _, e := range []Endpoint{l.A, l.B} {
+			if e.Node == "" || e.Port == "" {
+				return fmt.Errorf("topology: link %d needs a node and a port on each end", i)
+			}
+			if !nodes[e.Node] {
+				return fmt.Errorf("topology: link %d references undeclared node %q", i, e.Node)
+			}
+			key := Endpoint{Node: e.Node, Port: portKey(e.Port)}
+			if other, ok := ports[key]; ok {
+				if other == e {
+					return fmt.Errorf("topology: port %s is used by more than one link", e)
+				}
+				return fmt.Errorf("topology: ports %s and %s are the same interface", other, e)
+			}
+			ports[key] = e
+		}
+	}
+	return nil
+}
+
+// portKey returns the short form of a port name, such as "e0" for
+// "Ethernet0", "eth0" and "e0", or "f0/0" for "FastEthernet0/0".
+func portKey(port string) string {
+	port = strings.ToLower(port)
+	i := strings.IndexFunc(port, unicode.IsDigit)
+	if i <= 0 {
+		return port
+	}
+	return port[:1] + port[i:]
+}
@@ -0,0 +1,178 @@
+package topology_test
+
+import (
+	"errors"
+	"gons3"
+	"gons3/gons3test"
+	"gons3/topology"
+	"strings"
+	"testing"
+)
+
+const labSpec = `{
+	"project": {"name": "TestTopology", "auto_close": false, "grid_size": 50},
+	"variables": {"domain": "example.com"},
+	"nodes": [
+		{"name": "PC1", "template": "VPCS", "x": -100},
+		{"name": "PC2", "template": "VPCS", "x": 100},
+		{"name": "SW1", "template": "Ethernet switch", "y": 100}
+	],
+	"links": [
+		{"a": {"node": "PC1", "port": "e0"}, "b": {"node": "SW1", "port": "Ethernet0"}},
+		{"a": {"node": "SW1", "port": "Ethernet1"}, "b": {"node": "PC2", "port": "Ethernet0"}}
+	],
+	"drawings": [
+		{"svg": "<svg><text>lab</text></svg>", "x": 10, "y": 20}
+	]
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{"Valid", labSpec, ""},
+		{"UnknownField", `{"project": {"name": "p"}, "nodez": []}`, "unknown field"},
+		{"NoProjectName", `{"project": {}}`, "project name is required"},
+		{"NoTemplate", `{"project": {"name": "p"}, "nodes": [{"name": "n"}]}`, "has no template"},
+		{"DuplicateNode", `{"project": {"name": "p"}, "nodes": [{"name": "n", "template": "VPCS"}, {"name": "n", "template": "VPCS"}]}`, "more than once"},
+		{"UndeclaredNode", `{"project": {"name": "p"}, "nodes": [{"name": "n", "template": "VPCS"}], "links": [{"a": {"node": "n", "port": "e0"}, "b": {"node": "m", "port": "e0"}}]}`, "undeclared node"},
+		{"PortReused", `{"project": {"name": "p"}, "nodes": [{"name": "n", "template": "VPCS"}], "links": [{"a": {"node": "n", "port": "e0"}, "b": {"node": "n", "port": "e0"}}]}`, "more than one link"},
+		{"PortAlias", `{"project": {"name": "p"}, "nodes": [{"name": "n", "template": "VPCS"}, {"name": "m", "template": "VPCS"}, {"name": "o", "template": "VPCS"}], "links": [{"a": {"node": "n", "port": "e0"}, "b": {"node": "m", "port": "e0"}}, {"a": {"node": "n", "port": "Ethernet0"}, "b": {"node": "o", "port": "e0"}}]}`, "same interface"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := topology.Parse([]byte(tt.spec))
+			if tt.err == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	s := gons3test.NewServer()
+	defer s.Close()
+	g := s.Client()
+
+	spec, err := topology.Parse([]byte(labSpec))
+	if err != nil {
+		t.Fatalf("Error parsing spec: %v", err)
+	}
+
+	plan, err := topology.Apply(g, spec)
+	if err != nil {
+		t.Fatalf("Error applying spec: %v", err)
+	}
+	if len(plan.Actions) != 7 {
+		t.Errorf("Expected actions: %v, got %v\n%v", 7, len(plan.Actions), plan)
+	}
+
+	proj, err := gons3.GetProject(g, plan.ProjectID)
+	if err != nil {
+		t.Fatalf("Error getting project: %v", err)
+	}
+	if proj.AutoClose || proj.GridSize != 50 {
+		t.Errorf("Expected auto_close false and grid_size 50, got %v and %v", proj.AutoClose, proj.GridSize)
+	}
+	if proj.Variables == nil || len(*proj.Variables) != 1 {
+		t.Errorf("Expected 1 variable, got %v", proj.Variables)
+	}
+	links, err := gons3.GetLinks(g, plan.ProjectID)
+	if err != nil {
+		t.Fatalf("Error getting links: %v", err)
+	}
+	if len(links) != 2 {
+		t.Errorf("Expected links: %v, got %v", 2, len(links))
+	}
+
+	plan, err = topology.NewPlan(g, spec)
+	if err != nil {
+		t.Fatalf("Error planning spec: %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Expected an empty plan, got\n%v", plan)
+	}
+
+	spec.Nodes[0].X = -200
+	spec.Nodes[1].Template = "Ethernet hub"
+	spec.Links = spec.Links[:1]
+	spec.Drawings = nil
+	*spec.Project.GridSize = 75
+
+	plan, err = topology.NewPlan(g, spec)
+	if err != nil {
+		t.Fatalf("Error planning spec: %v", err)
+	}
+	expected := strings.Join([]string{
+		"- link PC2:Ethernet0 <-> SW1:Ethernet1",
+		"- node PC2",
+		"- drawing at (10, 20)",
+		"~ project TestTopology (grid_size)",
+		"~ node PC1 (position)",
+		"+ node PC2",
+		"",
+	}, "\n")
+	if plan.String() != expected {
+		t.Errorf("Expected plan:\n%v\ngot:\n%v", expected, plan)
+	}
+	if err := plan.Apply(); err != nil {
+		t.Fatalf("Error applying plan: %v", err)
+	}
+
+	plan, err = topology.NewPlan(g, spec)
+	if err != nil {
+		t.Fatalf("Error planning spec: %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Expected an empty plan, got\n%v", plan)
+	}
+
+	if _, err := gons3.CloseProject(g, plan.ProjectID); err != nil {
+		t.Fatalf("Error closing project: %v", err)
+	}
+	if _, err := topology.NewPlan(g, spec); !errors.Is(err, topology.ErrProjectClosed) {
+		t.Errorf("Expected error: %v, got %v", topology.ErrProjectClosed, err)
+	}
+	if proj, err := gons3.GetProject(g, plan.ProjectID); err != nil || proj.IsOpened() {
+		t.Errorf("Expected the plan to leave the project closed, got %v, %v", proj.Status, err)
+	}
+	plan, err = topology.Apply(g, spec)
+	if err != nil {
+		t.Fatalf("Error applying spec: %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("Expected an empty plan, got\n%v", plan)
+	}
+}
+
+func TestPlanUnknownTemplate(t *testing.T) {
+	s := gons3test.NewServer()
+	defer s.Close()
+
+	spec := &topology.Spec{
+		Project: topology.ProjectSpec{Name: "TestPlanUnknownTemplate"},
+		Nodes:   []topology.NodeSpec{{Name: "R1", Template: "c7200"}},
+	}
+	if _, err := topology.NewPlan(s.Client(), spec); err == nil || !strings.Contains(err.Error(), "unknown template") {
+		t.Errorf("Expected unknown template error, got %v", err)
+	}
+
+	s.AddTemplate(map[string]interface{}{"name": "c7200", "template_type": "dynamips"})
+	plan, err := topology.Apply(s.Client(), spec)
+	if err != nil {
+		t.Fatalf("Error applying spec: %v", err)
+	}
+	nodes, err := gons3.GetNodes(s.Client(), plan.ProjectID)
+	if err != nil {
+		t.Fatalf("Error getting nodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].NodeType != "dynamips" || nodes[0].Name != "R1" {
+		t.Errorf("Expected dynamips node R1, got %+v", nodes)
+	}
+}
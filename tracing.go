@@ -0,0 +1,110 @@
+package gons3
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+)
+
+// Span models a single traced GNS3 API call.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans and propagates their context through request headers.
+// Tracer is implemented by adapters for tracing libraries, such as OpenTelemetry.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+	Inject(ctx context.Context, header http.Header)
+}
+
+// TracingClient is a GNS3Client that emits a span for every request sent by Client.
+// Spans are children of Context when it is set, otherwise of the request's context.
+// Requests are always cancelled by their own context, never by Context.
+type TracingClient struct {
+	Client  GNS3Client
+	Tracer  Tracer
+	Context context.Context
+}
+
+// WithContext returns a copy of the client whose spans are children of ctx.
+func (t TracingClient) WithContext(ctx context.Context) TracingClient {
+	t.Context = ctx
+	return t
+}
+
+// GetSchemeAuthority gets the scheme and authority of the wrapped client.
+func (t TracingClient) GetSchemeAuthority() string {
+	return t.Client.GetSchemeAuthority()
+}
+
+// Do sends the HTTP request with the wrapped client inside of a span.
+func (t TracingClient) Do(req *http.Request) (*http.Response, error) {
+	if t.Tracer == nil {
+		return t.Client.Do(req)
+	}
+
+	parent := req.Context()
+	if t.Context != nil {
+		parent = t.Context
+	}
+
+	endpoint := EndpointTemplate(req.URL.Path)
+	ctx, span := t.Tracer.Start(parent, req.Method+" "+endpoint)
+	defer span.End()
+	if t.Context != nil {
+		ctx = spanContext{Context: req.Context(), span: ctx}
+	}
+
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.String())
+	span.SetAttribute("gns3.endpoint", endpoint)
+	for name, id := range endpointIDs(req.URL.Path) {
+		span.SetAttribute(name, id)
+	}
+
+	// Propagate the span context without modifying the caller's request
+	req = req.WithContext(ctx)
+	req.Header = req.Header.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	t.Tracer.Inject(ctx, req.Header)
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return resp, err
+	}
+	span.SetAttribute("http.status_code", resp.StatusCode)
+
+	// Record the server error, leaving the body readable for the caller
+	if resp.StatusCode >= 400 {
+		serverErr := newServerError(req, resp)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(serverErr.GetBody()))
+		if msg := serverErr.GetMessage(); msg != "" {
+			span.SetAttribute("gns3.error_message", msg)
+		}
+		span.RecordError(serverErr)
+	}
+
+	return resp, nil
+}
+
+// spanContext carries the values of the span's context, such as the span
+// itself, with the cancellation and deadline of the request's context.
+type spanContext struct {
+	context.Context
+	span context.Context
+}
+
+func (s spanContext) Value(key interface{}) interface{} {
+	if value := s.span.Value(key); value != nil {
+		return value
+	}
+	return s.Context.Value(key)
+}
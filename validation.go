@@ -0,0 +1,129 @@
+package gons3
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// ValidationError models the jsonschema details of a GNS3 schema validation error.
+type ValidationError struct {
+	// Message is the jsonschema error message, such as "'name' is a required property".
+	Message string
+	// Validator is the failing jsonschema keyword, such as "required" or "type".
+	Validator string
+	// JSONPath is the path of the failing value in the request, such as "$.name".
+	// It is empty when the server did not report enough detail to determine it.
+	JSONPath string
+	// SchemaPath is the path of the failing schema, if reported by the server.
+	SchemaPath string
+	// Schema is the failing schema, if reported by the server.
+	Schema json.RawMessage
+}
+
+var (
+	validatorRegexp   = regexp.MustCompile(`Failed validating '(\w+)' in schema((?:\[[^\]]*\])*):\n`)
+	instanceRegexp    = regexp.MustCompile(`\nOn instance((?:\[[^\]]*\])*):\n`)
+	pathElementRegexp = regexp.MustCompile(`\[(?:'((?:[^'\\]|\\.)*)'|(\d+))\]`)
+	quotedRegexp      = regexp.MustCompile(`'((?:[^'\\]|\\.)*)'`)
+)
+
+// parseValidationError parses the jsonschema details from a GNS3 error message.
+// GNS3 reports "Invalid JSON: <message> in schema: <schema>", while the verbose
+// jsonschema format reports "<message>\n\nFailed validating '<validator>' in schema...".
+func parseValidationError(msg string) *ValidationError {
+	if m := validatorRegexp.FindStringSubmatchIndex(msg); m != nil {
+		v := &ValidationError{
+			Message:    strings.TrimSpace(msg[:m[0]]),
+			Validator:  msg[m[2]:m[3]],
+			SchemaPath: jsonPath(msg[m[4]:m[5]]),
+		}
+		schema := msg[m[1]:]
+		if i := instanceRegexp.FindStringSubmatchIndex(schema); i != nil {
+			v.JSONPath = jsonPath(schema[i[2]:i[3]])
+			schema = schema[:i[0]]
+		}
+		if schema = strings.TrimSpace(schema); json.Valid([]byte(schema)) {
+			v.Schema = json.RawMessage(schema)
+		}
+		if v.JSONPath != "" {
+			if name := propertyName(v.Message, v.Validator); name != "" {
+				v.JSONPath += "." + name
+			}
+		}
+		return v
+	}
+
+	i := strings.Index(msg, " in schema")
+	if i == -1 {
+		return nil
+	}
+	v := &ValidationError{Message: msg[:i]}
+	if schema := strings.TrimSpace(strings.TrimPrefix(msg[i:], " in schema:")); json.Valid([]byte(schema)) {
+		v.Schema = json.RawMessage(schema)
+	}
+	v.Validator = inferValidator(strings.TrimPrefix(v.Message, "Invalid JSON: "))
+	if name := propertyName(v.Message, v.Validator); name != "" {
+		v.JSONPath = "$." + name
+	}
+	return v
+}
+
+// inferValidator infers the jsonschema keyword from a jsonschema error message.
+func inferValidator(msg string) string {
+	switch {
+	case strings.Contains(msg, "is a required property"):
+		return "required"
+	case strings.HasPrefix(msg, "Additional properties are not allowed"):
+		return "additionalProperties"
+	case strings.Contains(msg, "is not of type"):
+		return "type"
+	case strings.Contains(msg, "is not one of"):
+		return "enum"
+	case strings.Contains(msg, "does not match"):
+		return "pattern"
+	case strings.Contains(msg, "is less than the minimum"):
+		return "minimum"
+	case strings.Contains(msg, "is greater than the maximum"):
+		return "maximum"
+	case strings.Contains(msg, "is not valid under any of the given schemas"):
+		return "anyOf"
+	case strings.Contains(msg, "is valid under each of"):
+		return "oneOf"
+	case strings.HasSuffix(msg, "is too long"):
+		if strings.HasPrefix(msg, "[") {
+			return "maxItems"
+		}
+		return "maxLength"
+	case strings.HasSuffix(msg, "is too short"):
+		if strings.HasPrefix(msg, "[") {
+			return "minItems"
+		}
+		return "minLength"
+	}
+	return ""
+}
+
+// propertyName gets the offending property name of required and additionalProperties errors.
+func propertyName(msg, validator string) string {
+	if validator != "required" && validator != "additionalProperties" {
+		return ""
+	}
+	if m := quotedRegexp.FindStringSubmatch(msg); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// jsonPath converts a Python style path, such as "['nodes'][0]", into "$.nodes[0]".
+func jsonPath(path string) string {
+	p := "$"
+	for _, m := range pathElementRegexp.FindAllStringSubmatch(path, -1) {
+		if m[2] != "" {
+			p += "[" + m[2] + "]"
+		} else {
+			p += "." + m[1]
+		}
+	}
+	return p
+}
@@ -0,0 +1,192 @@
+// https://github.com/GNS3/gns3-server/blob/2.2/gns3server/handlers/api/controller/notification_handler.py
+
+package gons3
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrStatusNotReached is returned when the wait for a status ends before
+// the status is reached. It wraps the context error.
+var ErrStatusNotReached = errors.New("status not reached")
+
+// PollInterval is the first interval between polls when the notification
+// stream is not available. The interval doubles up to MaxPollInterval.
+var PollInterval = 250 * time.Millisecond
+
+// MaxPollInterval is the longest interval between polls.
+var MaxPollInterval = 5 * time.Second
+
+// Notification models a message of the GNS3 project notification stream.
+type Notification struct {
+	Action string          `json:"action"`
+	Event  json.RawMessage `json:"event"`
+}
+
+// contextClient sends every request with the context of a wait, so a poll
+// in flight is cancelled with the wait.
+type contextClient struct {
+	GNS3Client
+	ctx context.Context
+}
+
+func (c contextClient) Do(req *http.Request) (*http.Response, error) {
+	return c.GNS3Client.Do(req.WithContext(c.ctx))
+}
+
+// WaitForProjectStatus waits until the project has the specified status,
+// such as "opened" or "closed", or the context is done.
+func WaitForProjectStatus(ctx context.Context, g GNS3Client, projectID, status string) (Project, error) {
+	if projectID == "" {
+		return Project{}, ErrEmptyID
+	}
+
+	var proj Project
+	check := func() (bool, error) {
+		p, err := GetProject(contextClient{g, ctx}, projectID)
+		if err != nil {
+			return false, err
+		}
+		proj = p
+		return proj.Status == status, nil
+	}
+	notified := func(n Notification) bool {
+		switch n.Action {
+		case "project.updated", "project.opened", "project.closed":
+		default:
+			return false
+		}
+		p := Project{}
+		if err := json.Unmarshal(n.Event, &p); err != nil || p.ProjectID != projectID {
+			return false
+		}
+		if n.Action == "project.closed" {
+			p.Status = "closed"
+		}
+		proj = p
+		return proj.Status == status
+	}
+
+	err := waitFor(ctx, g, projectID, check, notified)
+	return proj, err
+}
+
+// WaitForNodeStatus waits until the node has the specified status, such as
+// "started" or "stopped", or the context is done.
+func WaitForNodeStatus(ctx context.Context, g GNS3Client, projectID, nodeID, status string) (Node, error) {
+	if projectID == "" || nodeID == "" {
+		return Node{}, ErrEmptyID
+	}
+
+	var node Node
+	check := func() (bool, error) {
+		n, err := GetNode(contextClient{g, ctx}, projectID, nodeID)
+		if err != nil {
+			return false, err
+		}
+		node = n
+		return node.Status == status, nil
+	}
+	notified := func(n Notification) bool {
+		if n.Action != "node.updated" {
+			return false
+		}
+		updated := Node{}
+		if err := json.Unmarshal(n.Event, &updated); err != nil || updated.NodeID != nodeID {
+			return false
+		}
+		node = updated
+		return node.Status == status
+	}
+
+	err := waitFor(ctx, g, projectID, check, notified)
+	return node, err
+}
+
+// waitFor checks the resource, then waits for a notification that reaches
+// the status. It polls with backoff if the notification stream is not
+// available or ends. The checks must be sent with ctx.
+func waitFor(ctx context.Context, g GNS3Client, projectID string, check func() (bool, error), notified func(Notification) bool) error {
+	check = checkContext(ctx, check)
+	if done, err := check(); done || err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if stream, err := openNotifications(ctx, g, projectID); err == nil {
+		defer stream.Body.Close()
+
+		// Check again in case the status changed before the stream opened.
+		if done, err := check(); done || err != nil {
+			return err
+		}
+		s := bufio.NewScanner(stream.Body)
+		s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for s.Scan() {
+			n := Notification{}
+			if err := json.Unmarshal(s.Bytes(), &n); err != nil {
+				continue
+			}
+			if notified(n) {
+				return nil
+			}
+		}
+	}
+
+	interval := PollInterval
+	for {
+		if ctx.Err() != nil {
+			return Wrap(ErrStatusNotReached, ctx.Err())
+		}
+		if done, err := check(); done || err != nil {
+			return err
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return Wrap(ErrStatusNotReached, ctx.Err())
+		case <-t.C:
+		}
+		if interval *= 2; interval > MaxPollInterval {
+			interval = MaxPollInterval
+		}
+	}
+}
+
+// checkContext returns ErrStatusNotReached for checks that fail because ctx is done.
+func checkContext(ctx context.Context, check func() (bool, error)) func() (bool, error) {
+	return func() (bool, error) {
+		done, err := check()
+		if err != nil && ctx.Err() != nil {
+			return false, Wrap(ErrStatusNotReached, ctx.Err())
+		}
+		return done, err
+	}
+}
+
+// openNotifications opens the notification stream of a project.
+func openNotifications(ctx context.Context, g GNS3Client, projectID string) (*http.Response, error) {
+	path := "/v2/projects/" + url.PathEscape(projectID) + "/notifications"
+	req, err := http.NewRequest("GET", g.GetSchemeAuthority()+path, nil)
+	if err != nil {
+		return nil, Wrap(ErrFailedToCreateRequest, err)
+	}
+	resp, err := g.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, Wrap(ErrRequestFailed, err)
+	}
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		return nil, Wrap(ErrUnexpectedStatusCode, newServerError(req, resp))
+	}
+	return resp, nil
+}
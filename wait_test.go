@@ -0,0 +1,66 @@
+package gons3_test
+
+import (
+	"context"
+	"errors"
+	"gons3"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// hangingClient answers the first node request with a stopped node, then
+// holds every request until its context is done. The notification stream
+// is not available.
+type hangingClient struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (h *hangingClient) GetSchemeAuthority() string {
+	return "http://gns3.test"
+}
+
+func (h *hangingClient) Do(req *http.Request) (*http.Response, error) {
+	h.mu.Lock()
+	h.calls++
+	first := h.calls == 1
+	h.mu.Unlock()
+
+	var resp *http.Response
+	switch {
+	case first:
+		resp = response(200, "application/json", `{"node_id": "n", "project_id": "p", "status": "stopped"}`)
+	case req.URL.Path == "/v2/projects/p/notifications":
+		resp = response(404, "application/json", `{"message": "Not Found", "status": 404}`)
+	default:
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	resp.Request = req
+	return resp, nil
+}
+
+func TestWaitForNodeStatusCancelsPoll(t *testing.T) {
+	interval := gons3.PollInterval
+	gons3.PollInterval = time.Millisecond
+	defer func() { gons3.PollInterval = interval }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := gons3.WaitForNodeStatus(ctx, &hangingClient{}, "p", "n", "started")
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, gons3.ErrStatusNotReached) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected errors: %v and %v, got %v", gons3.ErrStatusNotReached, context.DeadlineExceeded, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the wait to end with its context")
+	}
+}